@@ -0,0 +1,38 @@
+package varsig
+
+// DecodeBytes converts the start of the provided data into one of the
+// Varsig types provided by the DefaultRegistry and returns the number of
+// bytes consumed.  Unlike DecodeStream, the data is parsed in place,
+// without a reader.
+func DecodeBytes(data []byte, opts ...DecodeOption) (Varsig, int, error) {
+	return DefaultRegistry().DecodeBytes(data, opts...)
+}
+
+// DecodeBytes converts the start of the provided data into one of the
+// registered Varsig types and returns the number of bytes consumed, with
+// the behavior changed by the provided options.
+func (rs Registry) DecodeBytes(data []byte, opts ...DecodeOption) (Varsig, int, error) {
+	d := newSliceReader(data, opts)
+
+	vs, err := rs.decode(&d)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return vs, d.offset, nil
+}
+
+// expectAlgorithm reads the prefix, version and algorithm fields, and
+// checks that the algorithm is the expected one.
+func (d *decodeReader) expectAlgorithm(algo Algorithm) error {
+	got, algoOff, err := d.header()
+	if err != nil {
+		return err
+	}
+
+	if got != algo {
+		return unknownAlgorithm(got, algoOff)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package varsig_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, vs := range appendVarsigs {
+			t.Run(vs.String(), func(t *testing.T) {
+				t.Parallel()
+
+				data := append(vs.Encode(), 0xde, 0xad)
+
+				decoded, n, err := varsig.DecodeBytes(data, varsig.WithWebAuthn())
+				require.NoError(t, err)
+				assert.Equal(t, vs, decoded)
+				assert.Equal(t, vs.EncodedLen(), n)
+
+				decoded, n, err = varsig.DefaultRegistry().DecodeBytes(data, varsig.WithWebAuthn())
+				require.NoError(t, err)
+				assert.Equal(t, vs, decoded)
+				assert.Equal(t, vs.EncodedLen(), n)
+			})
+		}
+	})
+
+	t.Run("passes - concrete types", func(t *testing.T) {
+		t.Parallel()
+
+		var eddsa varsig.EdDSAVarsig
+		n, err := eddsa.DecodeBytes(mustDecodeHex(t, "3401ed01ed011371ff"))
+		require.NoError(t, err)
+		assert.Equal(t, 8, n)
+		assert.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), eddsa)
+
+		var ecdsa varsig.ECDSAVarsig
+		n, err = ecdsa.DecodeBytes(varsig.EIP712().Encode())
+		require.NoError(t, err)
+		assert.Equal(t, len(varsig.EIP712().Encode()), n)
+		assert.Equal(t, varsig.EIP712(), ecdsa)
+
+		var rsa varsig.RSAVarsig
+		n, err = rsa.DecodeBytes(mustDecodeHex(t, "3401852412800271"))
+		require.NoError(t, err)
+		assert.Equal(t, 8, n)
+		assert.Equal(t, varsig.RS256(256, varsig.PayloadEncodingDAGCBOR), rsa)
+	})
+
+	t.Run("fails - same errors as DecodeStream", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name string
+			hex  string
+		}{
+			{name: "empty", hex: ""},
+			{name: "wrong prefix", hex: "42"},
+			{name: "truncated prefix", hex: "b4"},
+			{name: "no version", hex: "34"},
+			{name: "unsupported version", hex: "3402"},
+			{name: "v0 header", hex: "34ed01ed011371"},
+			{name: "explicit v0 header", hex: "3400ed01ed011371"},
+			{name: "unknown algorithm", hex: "340164"},
+			{name: "unknown curve", hex: "3401ed0142"},
+			{name: "unknown hash", hex: "3401ec01802442"},
+			{name: "truncated hash", hex: "3401ec018024"},
+			{name: "no key length", hex: "340185241280"},
+			{name: "no payload encoding", hex: "34018524128002"},
+			{name: "unsupported payload encoding", hex: "3401852412800242"},
+			{name: "incomplete nested payload encoding", hex: "3401ec01e70191c303"},
+			{name: "unsupported nested payload encoding", hex: "3401ec01e70191c30342"},
+			{name: "overflow", hex: "3401852412ffffffffffffffffffff0171"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data := mustDecodeHex(t, tc.hex)
+
+				_, expected := varsig.DecodeStream(bytes.NewReader(data))
+				require.Error(t, expected)
+
+				vs, n, err := varsig.DecodeBytes(data)
+				require.EqualError(t, err, expected.Error())
+				assert.Nil(t, vs)
+				assert.Zero(t, n)
+			})
+		}
+	})
+
+	t.Run("fails - wrong concrete type", func(t *testing.T) {
+		t.Parallel()
+
+		var eddsa varsig.EdDSAVarsig
+		n, err := eddsa.DecodeBytes(varsig.EIP712().Encode())
+		require.ErrorIs(t, err, varsig.ErrUnknownAlgorithm)
+		assert.Zero(t, n)
+		assert.Equal(t, varsig.EdDSAVarsig{}, eddsa)
+	})
+}
+
+// TestDecodeBytesAllocs can't run in parallel because AllocsPerRun doesn't
+// support it.
+func TestDecodeBytesAllocs(t *testing.T) {
+	eddsa := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR).Encode()
+	ecdsa := varsig.EIP712().Encode()
+	rsa := varsig.RS256(256, varsig.PayloadEncodingEIP191Cbor).Encode()
+
+	var (
+		eddsaVarsig varsig.EdDSAVarsig
+		ecdsaVarsig varsig.ECDSAVarsig
+		rsaVarsig   varsig.RSAVarsig
+	)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = eddsaVarsig.DecodeBytes(eddsa)
+		_, _ = ecdsaVarsig.DecodeBytes(ecdsa)
+		_, _ = rsaVarsig.DecodeBytes(rsa)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := varsig.RS256(256, varsig.PayloadEncodingDAGCBOR).Encode()
+
+	b.Run("DecodeStream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = varsig.DecodeStream(bytes.NewReader(data))
+		}
+	})
+
+	b.Run("Registry.DecodeStream", func(b *testing.B) {
+		b.ReportAllocs()
+		reg := varsig.DefaultRegistry()
+		for i := 0; i < b.N; i++ {
+			_, _ = reg.DecodeStream(bytes.NewReader(data))
+		}
+	})
+
+	b.Run("DecodeBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = varsig.DecodeBytes(data)
+		}
+	})
+
+	b.Run("RSAVarsig.DecodeBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		var vs varsig.RSAVarsig
+		for i := 0; i < b.N; i++ {
+			_, _ = vs.DecodeBytes(data)
+		}
+	})
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	data, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return data
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// decodeResult is the description of a decoded varsig.  Trailing is the
+// hexadecimal encoding of the bytes following the varsig, which strict
+// decoding (and unmarshaling) rejects.
+type decodeResult struct {
+	Input      string             `json:"input"`
+	Hex        string             `json:"hex,omitempty"`
+	Varsig     string             `json:"varsig,omitempty"`
+	Parameters []varsig.Parameter `json:"parameters,omitempty"`
+	Trailing   string             `json:"trailing,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: varsig decode [flags] [varsig...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Decodes each varsig (or each line of stdin) and prints its parameters.")
+		fmt.Fprintln(stderr, "Exits with status 1 when a varsig can't be decoded or is followed by other bytes.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", formatAuto, "input format: auto, hex, base64, multibase or raw (stdin only)")
+	asJSON := fs.Bool("json", false, "print one JSON object per varsig")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	inputs, err := readInputs(fs.Args(), stdin, *format)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	code := exitOK
+	enc := json.NewEncoder(stdout)
+
+	for i, in := range inputs {
+		res := decodeInput(in)
+		if res.Error != "" || res.Trailing != "" {
+			code = exitFailure
+		}
+
+		if *asJSON {
+			if err := enc.Encode(res); err != nil {
+				fmt.Fprintf(stderr, "varsig: %v\n", err)
+				return exitFailure
+			}
+
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		printDecodeResult(stdout, res)
+	}
+
+	return code
+}
+
+func decodeInput(in input) decodeResult {
+	res := decodeResult{Input: in.text}
+
+	if in.err != nil {
+		res.Error = in.err.Error()
+		return res
+	}
+
+	res.Hex = hex.EncodeToString(in.data)
+
+	vs, n, err := varsig.DecodeBytes(in.data, webAuthn)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Varsig = fmt.Sprint(vs)
+	res.Parameters = varsig.Describe(vs)
+	res.Trailing = hex.EncodeToString(in.data[n:])
+
+	return res
+}
+
+func printDecodeResult(w io.Writer, res decodeResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+
+	fmt.Fprintf(tw, "input:\t%s\n", res.Input)
+
+	if res.Hex != "" {
+		fmt.Fprintf(tw, "hex:\t%s\n", res.Hex)
+	}
+
+	if res.Error != "" {
+		fmt.Fprintf(tw, "error:\t%s\n", res.Error)
+	}
+
+	if res.Varsig != "" {
+		fmt.Fprintf(tw, "varsig:\t%s\n", res.Varsig)
+	}
+
+	for _, p := range res.Parameters {
+		fmt.Fprintf(tw, "%s:\t%s (0x%x)\n", p.Name, p.Text, p.Value)
+	}
+
+	if res.Trailing != "" {
+		fmt.Fprintf(tw, "trailing:\t%d bytes follow the varsig (%s)\n", len(res.Trailing)/2, res.Trailing)
+	}
+
+	_ = tw.Flush()
+}
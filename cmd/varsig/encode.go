@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// Output formats accepted by the -output flag.
+const (
+	outputHex       = "hex"
+	outputBase64    = "base64"
+	outputMultibase = "multibase"
+)
+
+// joseConstructor builds the varsig for an algorithm name.  The key
+// length is only used by RSA algorithms.
+type joseConstructor func(keyLen uint64, payEnc varsig.PayloadEncoding) (varsig.Varsig, error)
+
+// joseAlgorithms are the JOSE algorithm names.  EdDSA, which leaves the
+// curve to the key, means Ed25519 like it does in UCAN.
+var joseAlgorithms = map[string]joseConstructor{
+	"EdDSA":   joseFixed(varsig.Ed25519),
+	"Ed25519": joseFixed(varsig.Ed25519),
+	"Ed448":   joseFixed(varsig.Ed448),
+	"ES256":   joseFixed(varsig.ES256),
+	"ES256K":  joseFixed(varsig.ES256K),
+	"ES384":   joseFixed(varsig.ES384),
+	"ES512":   joseFixed(varsig.ES512),
+	"RS256":   joseRSA(varsig.RS256),
+	"RS384":   joseRSA(varsig.RS384),
+	"RS512":   joseRSA(varsig.RS512),
+}
+
+// ethereumAlgorithms are the Ethereum signing schemes, which aren't JOSE
+// algorithms but are accepted by -alg too.
+var ethereumAlgorithms = map[string]joseConstructor{
+	"EIP191": func(_ uint64, payEnc varsig.PayloadEncoding) (varsig.Varsig, error) {
+		return varsig.EIP191(payEnc)
+	},
+	"EIP712": func(_ uint64, payEnc varsig.PayloadEncoding) (varsig.Varsig, error) {
+		if payEnc != varsig.PayloadEncodingEIP712 {
+			return nil, fmt.Errorf("%w for EIP712: %v", varsig.ErrUnsupportedPayloadEncoding, payEnc)
+		}
+
+		return varsig.EIP712(), nil
+	},
+}
+
+var multibaseNames = map[string]varsig.Multibase{
+	"base16":       varsig.MultibaseBase16,
+	"base16upper":  varsig.MultibaseBase16Upper,
+	"base32":       varsig.MultibaseBase32,
+	"base32upper":  varsig.MultibaseBase32Upper,
+	"base58btc":    varsig.MultibaseBase58BTC,
+	"base64":       varsig.MultibaseBase64,
+	"base64pad":    varsig.MultibaseBase64Pad,
+	"base64url":    varsig.MultibaseBase64URL,
+	"base64urlpad": varsig.MultibaseBase64URLPad,
+}
+
+func joseFixed[T varsig.Varsig](ctor func(varsig.PayloadEncoding) T) joseConstructor {
+	return func(_ uint64, payEnc varsig.PayloadEncoding) (varsig.Varsig, error) {
+		return ctor(payEnc), nil
+	}
+}
+
+func joseRSA(ctor func(uint64, varsig.PayloadEncoding) varsig.RSAVarsig) joseConstructor {
+	return func(keyLen uint64, payEnc varsig.PayloadEncoding) (varsig.Varsig, error) {
+		if keyLen == 0 {
+			return nil, errors.New("-rsa-keylen is required for RSA algorithms")
+		}
+
+		return ctor(keyLen, payEnc), nil
+	}
+}
+
+func runEncode(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("encode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: varsig encode [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Builds a varsig from an algorithm name or a descriptor and prints it.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintf(stderr, "JOSE algorithms: %s\n", strings.Join(sortedKeys(joseAlgorithms), ", "))
+		fmt.Fprintf(stderr, "Ethereum algorithms: %s\n", strings.Join(sortedKeys(ethereumAlgorithms), ", "))
+		fmt.Fprintln(stderr, "Descriptor examples: eddsa:ed25519:sha2-512:dag-cbor, rsa:sha2-256:2048:verbatim")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	alg := fs.String("alg", "", "JOSE or Ethereum algorithm name (e.g. ES256 or EIP191)")
+	payload := fs.String("payload", "dag-cbor", "payload encoding (e.g. verbatim, dag-cbor, eip191-raw)")
+	keyLen := fs.Uint64("rsa-keylen", 0, "RSA key length in bytes (e.g. 256 for a 2048-bit key)")
+	descriptor := fs.String("descriptor", "", "varsig descriptor, instead of -alg and -payload")
+	output := fs.String("output", outputHex, "output format: hex, base64 or multibase")
+	base := fs.String("base", "base64url", "multibase base used by the multibase output format")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() > 0 || (*alg == "") == (*descriptor == "") {
+		fmt.Fprintln(stderr, "varsig: exactly one of -alg or -descriptor is required")
+		fs.Usage()
+
+		return exitUsage
+	}
+
+	// Check the output flags before building the varsig, so that usage
+	// errors are reported whatever the varsig.
+	if err := checkOutput(*output, *base); err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	vs, err := buildVarsig(*alg, *payload, *keyLen, *descriptor)
+	if err == nil {
+		err = checkDecodable(vs)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	text, err := formatOutput(vs, *output, *base)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	fmt.Fprintln(stdout, text)
+
+	return exitOK
+}
+
+func buildVarsig(alg, payload string, keyLen uint64, descriptor string) (varsig.Varsig, error) {
+	if descriptor != "" {
+		return varsig.Parse(descriptor)
+	}
+
+	ctor, ok := lookupFold(joseAlgorithms, alg)
+	if !ok {
+		ctor, ok = lookupFold(ethereumAlgorithms, alg)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm %q", alg)
+	}
+
+	payEnc, err := varsig.ParsePayloadEncoding(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctor(keyLen, payEnc)
+}
+
+// checkDecodable returns an error if the varsig would be rejected when
+// decoded, such as the JWT and DAG-PB payload encodings which can be
+// encoded but aren't decoded.
+func checkDecodable(vs varsig.Varsig) error {
+	m, ok := vs.(encoding.BinaryMarshaler)
+	if !ok {
+		return fmt.Errorf("can't encode %T", vs)
+	}
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if _, err := varsig.DecodeStrict(data, webAuthn); err != nil {
+		return fmt.Errorf("refusing to encode %v, which can't be decoded: %w", vs, err)
+	}
+
+	return nil
+}
+
+// checkOutput returns an error if the output format or the multibase base
+// is unknown.
+func checkOutput(output, base string) error {
+	switch output {
+	case outputHex, outputBase64:
+		return nil
+	case outputMultibase:
+		if _, ok := lookupFold(multibaseNames, base); !ok {
+			return fmt.Errorf("unknown multibase base %q", base)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	}
+}
+
+// formatOutput encodes the varsig in the output format, which must have
+// been checked with checkOutput.  The varsig is encoded with
+// MarshalBinary, which returns an error where Encode would panic (e.g. for
+// an unspecified payload encoding.)
+func formatOutput(vs varsig.Varsig, output, base string) (string, error) {
+	m, ok := vs.(encoding.BinaryMarshaler)
+	if !ok {
+		return "", fmt.Errorf("can't encode %T", vs)
+	}
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	switch output {
+	case outputHex:
+		return hex.EncodeToString(data), nil
+	case outputBase64:
+		return base64.RawStdEncoding.EncodeToString(data), nil
+	default: // outputMultibase
+		mb, _ := lookupFold(multibaseNames, base)
+
+		return varsig.EncodeMultibase(vs, mb)
+	}
+}
+
+// lookupFold returns the value whose key matches name, ignoring case.
+func lookupFold[T any](m map[string]T, name string) (T, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// Input formats accepted by the -format flag.
+const (
+	formatAuto      = "auto"
+	formatHex       = "hex"
+	formatBase64    = "base64"
+	formatMultibase = "multibase"
+	formatRaw       = "raw"
+)
+
+// varsigPrefixHex is the hexadecimal encoding of the varsig prefix, which
+// is used to recognize hexadecimal input.
+const varsigPrefixHex = "34"
+
+var errUnrecognizedInput = errors.New("unrecognized input format")
+
+// webAuthn is passed to every decode, since the encode command can
+// produce varsigs using the WebAuthn payload encodings.
+var webAuthn = varsig.WithWebAuthn()
+
+// input is a varsig read from the command line or stdin.
+type input struct {
+	text string
+	data []byte
+	err  error
+}
+
+// readInputs returns the varsigs provided as arguments or, if there are
+// none, read from stdin.
+func readInputs(args []string, stdin io.Reader, format string) ([]input, error) {
+	if len(args) == 0 && format == formatRaw {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		return []input{{text: "(stdin)", data: data}}, nil
+	}
+
+	if len(args) == 0 {
+		var err error
+
+		args, err = readLines(stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inputs := make([]input, 0, len(args))
+
+	for _, arg := range args {
+		data, err := parseInput(arg, format)
+		inputs = append(inputs, input{text: arg, data: data, err: err})
+	}
+
+	return inputs, nil
+}
+
+// readLines returns the non-empty lines read from r.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseInput converts the textual representation of a varsig to its
+// bytes.  When the format is auto, hexadecimal (optionally prefixed by
+// 0x), multibase and base64 (standard or URL, padded or not) are tried in
+// that order.
+func parseInput(text, format string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+
+	switch format {
+	case formatHex:
+		return hex.DecodeString(strings.TrimPrefix(text, "0x"))
+	case formatBase64:
+		return decodeBase64(text)
+	case formatMultibase:
+		return varsig.DecodeMultibaseRaw(text)
+	case formatRaw:
+		return []byte(text), nil
+	case formatAuto:
+		return parseAuto(text)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func parseAuto(text string) ([]byte, error) {
+	if h, ok := strings.CutPrefix(text, "0x"); ok || strings.HasPrefix(text, varsigPrefixHex) {
+		if data, err := hex.DecodeString(h); err == nil {
+			return data, nil
+		}
+	}
+
+	if data, err := varsig.DecodeMultibaseRaw(text); err == nil && bytes.HasPrefix(data, []byte{0x34}) {
+		return data, nil
+	}
+
+	if data, err := decodeBase64(text); err == nil {
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", errUnrecognizedInput, text)
+}
+
+func decodeBase64(text string) ([]byte, error) {
+	text = strings.TrimRight(text, "=")
+
+	if strings.ContainsAny(text, "-_") {
+		return base64.RawURLEncoding.DecodeString(text)
+	}
+
+	return base64.RawStdEncoding.DecodeString(text)
+}
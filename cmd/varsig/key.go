@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+var errUnsupportedKey = errors.New("unsupported key")
+
+// key is a public key along with its private key, when available.
+type key struct {
+	public  crypto.PublicKey
+	private crypto.Signer
+}
+
+// jwk contains the JSON Web Key fields used by the supported key types.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	P   string `json:"p"`
+	Q   string `json:"q"`
+}
+
+// loadKey reads a PEM (PKCS#8, PKIX, SEC 1 or PKCS#1) or JWK encoded key
+// from the file.
+func loadKey(path string) (key, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- the path is provided by the user
+	if err != nil {
+		return key{}, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		return parseJWK(trimmed)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return key{}, fmt.Errorf("%s: neither a PEM nor a JWK key", path)
+	}
+
+	return parsePEM(block)
+}
+
+func parsePEM(block *pem.Block) (key, error) {
+	var (
+		k   any
+		err error
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		k, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		k, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		k, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		k, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		k, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return key{}, fmt.Errorf("%w: PEM block type %q", errUnsupportedKey, block.Type)
+	}
+
+	if err != nil {
+		return key{}, err
+	}
+
+	return newKey(k)
+}
+
+func newKey(k any) (key, error) {
+	switch k := k.(type) {
+	case *ecdsa.PrivateKey:
+		return key{public: &k.PublicKey, private: k}, nil
+	case ed25519.PrivateKey:
+		return key{public: k.Public(), private: k}, nil
+	case *rsa.PrivateKey:
+		return key{public: &k.PublicKey, private: k}, nil
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return key{public: k}, nil
+	default:
+		return key{}, fmt.Errorf("%w: %T", errUnsupportedKey, k)
+	}
+}
+
+func parseJWK(data []byte) (key, error) {
+	var j jwk
+	if err := json.Unmarshal(data, &j); err != nil {
+		return key{}, fmt.Errorf("malformed JWK: %w", err)
+	}
+
+	switch j.Kty {
+	case "EC":
+		return parseECJWK(j)
+	case "OKP":
+		return parseOKPJWK(j)
+	case "RSA":
+		return parseRSAJWK(j)
+	default:
+		return key{}, fmt.Errorf("%w: JWK key type %q", errUnsupportedKey, j.Kty)
+	}
+}
+
+func parseECJWK(j jwk) (key, error) {
+	var curve elliptic.Curve
+
+	switch j.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return key{}, fmt.Errorf("%w: JWK curve %q", errUnsupportedKey, j.Crv)
+	}
+
+	x, err := jwkInt(j.X, "x")
+	if err != nil {
+		return key{}, err
+	}
+
+	y, err := jwkInt(j.Y, "y")
+	if err != nil {
+		return key{}, err
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return key{}, errors.New("JWK point is not on the curve")
+	}
+
+	pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+	if j.D == "" {
+		return newKey(pub)
+	}
+
+	d, err := jwkInt(j.D, "d")
+	if err != nil {
+		return key{}, err
+	}
+
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return key{}, errors.New("JWK private key is out of range")
+	}
+
+	if dx, dy := curve.ScalarBaseMult(d.Bytes()); dx.Cmp(x) != 0 || dy.Cmp(y) != 0 {
+		return key{}, errors.New("JWK private key doesn't match the public key")
+	}
+
+	return newKey(&ecdsa.PrivateKey{PublicKey: *pub, D: d})
+}
+
+func parseOKPJWK(j jwk) (key, error) {
+	if j.Crv != "Ed25519" {
+		return key{}, fmt.Errorf("%w: JWK curve %q", errUnsupportedKey, j.Crv)
+	}
+
+	if j.D != "" {
+		seed, err := jwkBytes(j.D, "d")
+		if err != nil {
+			return key{}, err
+		}
+
+		if len(seed) != ed25519.SeedSize {
+			return key{}, fmt.Errorf("JWK Ed25519 private key must be %d bytes", ed25519.SeedSize)
+		}
+
+		priv := ed25519.NewKeyFromSeed(seed)
+
+		if j.X != "" {
+			x, err := jwkBytes(j.X, "x")
+			if err != nil {
+				return key{}, err
+			}
+
+			if !bytes.Equal(x, priv.Public().(ed25519.PublicKey)) {
+				return key{}, errors.New("JWK private key doesn't match the public key")
+			}
+		}
+
+		return newKey(priv)
+	}
+
+	x, err := jwkBytes(j.X, "x")
+	if err != nil {
+		return key{}, err
+	}
+
+	if len(x) != ed25519.PublicKeySize {
+		return key{}, fmt.Errorf("JWK Ed25519 public key must be %d bytes", ed25519.PublicKeySize)
+	}
+
+	return newKey(ed25519.PublicKey(x))
+}
+
+func parseRSAJWK(j jwk) (key, error) {
+	n, err := jwkInt(j.N, "n")
+	if err != nil {
+		return key{}, err
+	}
+
+	e, err := jwkInt(j.E, "e")
+	if err != nil {
+		return key{}, err
+	}
+
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return key{}, errors.New("JWK RSA exponent is too large")
+	}
+
+	pub := &rsa.PublicKey{N: n, E: int(e.Int64())}
+
+	if j.D == "" {
+		return newKey(pub)
+	}
+
+	d, err := jwkInt(j.D, "d")
+	if err != nil {
+		return key{}, err
+	}
+
+	p, err := jwkInt(j.P, "p")
+	if err != nil {
+		return key{}, err
+	}
+
+	q, err := jwkInt(j.Q, "q")
+	if err != nil {
+		return key{}, err
+	}
+
+	priv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+
+	if err := priv.Validate(); err != nil {
+		return key{}, fmt.Errorf("invalid JWK RSA key: %w", err)
+	}
+
+	priv.Precompute()
+
+	return newKey(priv)
+}
+
+func jwkBytes(value, name string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("JWK is missing %q", name)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWK %q: %w", name, err)
+	}
+
+	return b, nil
+}
+
+func jwkInt(value, name string) (*big.Int, error) {
+	b, err := jwkBytes(value, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
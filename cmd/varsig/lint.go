@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// Rules reported by the lint command.
+const (
+	ruleInvalid            = "invalid"
+	ruleVersion0           = "v0-header"
+	ruleUnknownAlgorithm   = "unknown-algorithm"
+	ruleNonCanonicalVarint = "non-canonical-varint"
+	ruleTrailingBytes      = "trailing-bytes"
+	ruleWeakHash           = "weak-hash"
+	ruleRSAKeyLength       = "rsa-key-length"
+)
+
+// weakHashes are the hash algorithms that shouldn't be used for
+// signatures anymore.
+var weakHashes = map[varsig.Hash]bool{
+	varsig.HashMd4:        true,
+	varsig.HashMd5:        true,
+	varsig.HashSha1:       true,
+	varsig.HashRipemd_160: true,
+}
+
+// finding is a problem found in a varsig.
+type finding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// lintResult is the outcome of linting a single varsig.
+type lintResult struct {
+	Source   string    `json:"source"`
+	Input    string    `json:"input"`
+	Varsig   string    `json:"varsig,omitempty"`
+	Findings []finding `json:"findings"`
+}
+
+// lintSource is a varsig read by the lint command, along with where it
+// was read from.
+type lintSource struct {
+	source string
+	text   string
+}
+
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: varsig lint [flags] [file or directory...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Audits newline-delimited varsigs read from the files (recursively for")
+		fmt.Fprintln(stderr, "directories) or stdin.  Rules:")
+		fmt.Fprintln(stderr)
+		fmt.Fprintf(stderr, "  %-21s the varsig can't be parsed\n", ruleInvalid)
+		fmt.Fprintf(stderr, "  %-21s the varsig uses the v0 header\n", ruleVersion0)
+		fmt.Fprintf(stderr, "  %-21s the signing algorithm is unknown\n", ruleUnknownAlgorithm)
+		fmt.Fprintf(stderr, "  %-21s a varint isn't minimally encoded\n", ruleNonCanonicalVarint)
+		fmt.Fprintf(stderr, "  %-21s bytes follow the varsig\n", ruleTrailingBytes)
+		fmt.Fprintf(stderr, "  %-21s the hash is MD4, MD5, SHA-1 or RIPEMD-160\n", ruleWeakHash)
+		fmt.Fprintf(stderr, "  %-21s the RSA key is shorter than -min-rsa-bits\n", ruleRSAKeyLength)
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Exits with status 1 when any varsig has findings.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", formatAuto, "input format: auto, hex, base64 or multibase")
+	asJSON := fs.Bool("json", false, "print one JSON object per varsig with findings")
+	all := fs.Bool("all", false, "also report varsigs without findings")
+	minRSABits := fs.Uint64("min-rsa-bits", 2048, "minimum RSA key length in bits")
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *format == formatRaw {
+		fmt.Fprintln(stderr, "varsig: the raw format can't be used with newline-delimited input")
+		return exitUsage
+	}
+
+	sources, err := readLintSources(fs.Args(), stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	code := exitOK
+	flagged := 0
+	enc := json.NewEncoder(stdout)
+
+	for _, src := range sources {
+		res := lintResult{Source: src.source, Input: src.text}
+
+		data, err := parseInput(src.text, *format)
+		if err != nil {
+			res.Findings = []finding{{Rule: ruleInvalid, Message: err.Error()}}
+		} else {
+			res.Varsig, res.Findings = lint(data, *minRSABits)
+		}
+
+		if len(res.Findings) > 0 {
+			code = exitFailure
+			flagged++
+		} else if !*all {
+			continue
+		}
+
+		if *asJSON {
+			if err := enc.Encode(res); err != nil {
+				fmt.Fprintf(stderr, "varsig: %v\n", err)
+				return exitFailure
+			}
+
+			continue
+		}
+
+		if len(res.Findings) == 0 {
+			fmt.Fprintf(stdout, "%s: ok: %s\n", res.Source, res.Varsig)
+		}
+
+		for _, f := range res.Findings {
+			fmt.Fprintf(stdout, "%s: %s: %s\n", res.Source, f.Rule, f.Message)
+		}
+	}
+
+	if !*asJSON {
+		fmt.Fprintf(stdout, "%d of %d varsigs have findings\n", flagged, len(sources))
+	}
+
+	return code
+}
+
+// readLintSources returns the non-empty lines of the named files, walking
+// directories recursively, or of stdin when no path is provided.
+func readLintSources(paths []string, stdin io.Reader) ([]lintSource, error) {
+	if len(paths) == 0 {
+		return appendLintSources(nil, "(stdin)", stdin)
+	}
+
+	var sources []lintSource
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			sources, err = appendLintSources(sources, path, f)
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
+
+func appendLintSources(sources []lintSource, name string, r io.Reader) ([]lintSource, error) {
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		if text := strings.TrimSpace(scanner.Text()); text != "" {
+			sources = append(sources, lintSource{source: fmt.Sprintf("%s:%d", name, line), text: text})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return sources, nil
+}
+
+// lint checks the encoded varsig and returns its description (when it
+// can be decoded) and the problems found.
+func lint(data []byte, minRSABits uint64) (string, []finding) {
+	// Non-canonical varints are accepted while decoding so that they're
+	// reported as such, along with any other finding.
+	r := bytes.NewReader(data)
+	vs, err := varsig.DecodeWithOptions(context.Background(), r, varsig.WithNonCanonical(), webAuthn)
+
+	// Only the bytes that were decoded are checked for non-canonical
+	// varints, so that trailing data isn't reported twice.
+	end := len(data)
+	if err == nil {
+		end -= r.Len()
+	}
+
+	findings := nonCanonicalVarints(data[:end])
+
+	if err != nil {
+		return "", append(findings, decodeFinding(data, err))
+	}
+
+	if r.Len() > 0 {
+		findings = append(findings, finding{
+			Rule:    ruleTrailingBytes,
+			Message: fmt.Sprintf("%d bytes follow the varsig", r.Len()),
+		})
+	}
+
+	if weakHashes[vs.Hash()] {
+		findings = append(findings, finding{
+			Rule:    ruleWeakHash,
+			Message: fmt.Sprintf("hash %v is weak", vs.Hash()),
+		})
+	}
+
+	// Compare in bytes, rounding the minimum up, so that neither side can
+	// overflow.
+	if v, ok := vs.(varsig.RSAVarsig); ok && v.KeyLength() < minRSABits/8+min(minRSABits%8, 1) {
+		findings = append(findings, finding{
+			Rule:    ruleRSAKeyLength,
+			Message: fmt.Sprintf("RSA key length is %d bits, less than %d", v.KeyLength()*8, minRSABits),
+		})
+	}
+
+	return fmt.Sprint(vs), findings
+}
+
+// decodeFinding returns the finding describing why the varsig couldn't be
+// decoded.
+func decodeFinding(data []byte, err error) finding {
+	if vers, algo, ok := version0(data); ok {
+		return finding{
+			Rule:    ruleVersion0,
+			Message: fmt.Sprintf("varsig %v header (algorithm %v)", vers, algo),
+		}
+	}
+
+	if errors.Is(err, varsig.ErrUnknownAlgorithm) {
+		return finding{Rule: ruleUnknownAlgorithm, Message: err.Error()}
+	}
+
+	return finding{Rule: ruleInvalid, Message: err.Error()}
+}
+
+// version0 reports whether the data starts with a v0 varsig header, which
+// either omits the version (the algorithm directly follows the prefix) or
+// sets it to zero.
+func version0(data []byte) (varsig.Version, varsig.Algorithm, bool) {
+	r := bytes.NewReader(data)
+
+	if pre, err := binary.ReadUvarint(r); err != nil || pre != varsig.Prefix {
+		return 0, 0, false
+	}
+
+	vers, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if vers >= 64 {
+		return varsig.Version0, varsig.Algorithm(vers), true
+	}
+
+	if varsig.Version(vers) != varsig.Version0 {
+		return 0, 0, false
+	}
+
+	algo, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return varsig.Version0, varsig.Algorithm(algo), true
+}
+
+// nonCanonicalVarints returns a finding for each varint that isn't
+// minimally encoded (a multi-byte varint whose last byte is zero.)  Every
+// field of a varsig header is a varint, so the data is split at each byte
+// without the continuation bit.
+func nonCanonicalVarints(data []byte) []finding {
+	findings := []finding{}
+	start := 0
+
+	for i, b := range data {
+		if b&0x80 != 0 {
+			continue
+		}
+
+		if i > start && b == 0 {
+			findings = append(findings, finding{
+				Rule:    ruleNonCanonicalVarint,
+				Message: fmt.Sprintf("varint at offset %d is encoded as % x", start, data[start:i+1]),
+			})
+		}
+
+		start = i + 1
+	}
+
+	return findings
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes - no findings", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, stderr := runCommand(t, "3401ed01ed011371\n\nuNAGFJBKAAnE\n3401ec018024128080c00171\n", "lint")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "0 of 3 varsigs have findings\n", stdout)
+	})
+
+	t.Run("fails - findings", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name     string
+			input    string
+			rules    []string
+			messages []string
+		}{
+			{name: "MD5", input: "34018524d501800271", rules: []string{ruleWeakHash}, messages: []string{"hash md5 is weak"}},
+			{name: "SHA-1", input: "3401ec0180241171", rules: []string{ruleWeakHash}, messages: []string{"hash sha1 is weak"}},
+			{name: "short RSA key", input: "3401852412800171", rules: []string{ruleRSAKeyLength}, messages: []string{"RSA key length is 1024 bits, less than 2048"}},
+			{name: "v0 header", input: "34ed01ed011371", rules: []string{ruleVersion0}, messages: []string{"varsig v0 header (algorithm EdDSA)"}},
+			{name: "explicit v0 header", input: "3400ed01ed01ed011371", rules: []string{ruleVersion0}, messages: []string{"varsig v0 header (algorithm EdDSA)"}},
+			{name: "non-canonical varint", input: "348100ed01ed011371", rules: []string{ruleNonCanonicalVarint}, messages: []string{"varint at offset 1 is encoded as 81 00"}},
+			{name: "unknown algorithm", input: "3401990112800271", rules: []string{ruleUnknownAlgorithm}, messages: []string{"unknown signing algorithm: 0x99"}},
+			{name: "trailing bytes", input: "3401ed01ed01137100", rules: []string{ruleTrailingBytes}, messages: []string{"1 bytes follow the varsig"}},
+			{name: "invalid", input: "3501ed01ed011371", rules: []string{ruleInvalid}, messages: []string{"varsig prefix not found"}},
+			{
+				name:     "several",
+				input:    "340185241180800071",
+				rules:    []string{ruleNonCanonicalVarint, ruleWeakHash, ruleRSAKeyLength},
+				messages: []string{"varint at offset 5 is encoded as 80 80 00", "hash sha1 is weak", "RSA key length is 0 bits, less than 2048"},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				code, stdout, _ := runCommand(t, tc.input+"\n", "lint", "-json")
+				require.Equal(t, exitFailure, code)
+
+				var res lintResult
+				require.NoError(t, json.Unmarshal([]byte(stdout), &res))
+				require.Equal(t, "(stdin):1", res.Source)
+				require.Equal(t, tc.input, res.Input)
+				require.Len(t, res.Findings, len(tc.rules))
+
+				for i, f := range res.Findings {
+					require.Equal(t, tc.rules[i], f.Rule)
+					require.Contains(t, f.Message, tc.messages[i])
+				}
+			})
+		}
+	})
+
+	t.Run("passes - minimum RSA key length", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, _ := runCommand(t, "3401852412800171\n", "lint", "-min-rsa-bits", "1024")
+		require.Equal(t, exitOK, code)
+
+		// 2^61 bytes is 2^64 bits, which would wrap to 0 if computed in bits.
+		code, _, _ = runCommand(t, "340185241280808080808080802071\n", "lint")
+		require.Equal(t, exitOK, code)
+	})
+
+	t.Run("fails - minimum RSA key length not a multiple of 8", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "3401852412800171\n", "lint", "-min-rsa-bits", "1025")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stdout, "RSA key length is 1024 bits, less than 1025")
+	})
+
+	t.Run("passes - directories", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "archive"), 0o700))
+		writeFile(t, dir, "tokens.txt", []byte("3401ed01ed011371\n3401ec0180241171\n"))
+		writeFile(t, filepath.Join(dir, "archive"), "old.txt", []byte("\nNO0B7QETcQ\n"))
+
+		code, stdout, _ := runCommand(t, "", "lint", "-all", dir)
+		require.Equal(t, exitFailure, code)
+		require.Equal(t, strings.Join([]string{
+			filepath.Join(dir, "archive", "old.txt") + ":2: v0-header: varsig v0 header (algorithm EdDSA)",
+			filepath.Join(dir, "tokens.txt") + ":1: ok: EdDSA/Ed25519/sha2-512/dag-cbor v1",
+			filepath.Join(dir, "tokens.txt") + ":2: weak-hash: hash sha1 is weak",
+			"2 of 3 varsigs have findings",
+		}, "\n")+"\n", stdout)
+	})
+
+	t.Run("fails - usage", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, _ := runCommand(t, "", "lint", "-format", "raw")
+		require.Equal(t, exitUsage, code)
+
+		code, _, stderr := runCommand(t, "", "lint", filepath.Join(t.TempDir(), "missing"))
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stderr, "no such file or directory")
+	})
+}
@@ -0,0 +1,95 @@
+// Command varsig inspects varsig headers.
+//
+// Usage:
+//
+//	varsig decode [-format auto|hex|base64|multibase|raw] [-json] [varsig...]
+//	varsig encode (-alg <algorithm name> [-payload <encoding>] [-rsa-keylen <bytes>] | -descriptor <descriptor>)
+//	              [-output hex|base64|multibase] [-base <multibase base>]
+//	varsig sign -key <file> -payload <file> [-varsig <varsig>] [-output hex|base64|multibase]
+//	varsig verify -key <file> -payload <file> [-varsig <varsig>] (-signature <sig> | -signature-file <file>)
+//	varsig lint [-format auto|hex|base64|multibase] [-json] [-all] [-min-rsa-bits <bits>] [file or directory...]
+//
+// The decode command reads varsigs from the command line arguments or, if
+// there are none, from stdin (one per line, unless the raw format is
+// used.)
+//
+// The sign and verify commands accept PEM or JWK keys.  When no varsig is
+// provided, the usual varsig for the key is used (for instance ES256 for a
+// P-256 key.)  Mismatches between the key and the varsig (such as the
+// curve or the RSA key length) are reported as errors.
+//
+// The lint command audits stored varsigs, one per line, read from files,
+// directories or stdin.  It reports weak hashes, short RSA keys, v0
+// headers, non-canonical varints and unknown algorithms, and exits with
+// status 1 when any varsig has findings.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes returned by the subcommands.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is a varsig subcommand.
+type command struct {
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"decode": {summary: "decode and describe varsig headers", run: runDecode},
+	"encode": {summary: "build a varsig header from flags", run: runEncode},
+	"sign":   {summary: "sign a payload as described by a varsig", run: runSign},
+	"verify": {summary: "verify a payload's signature as described by a varsig", run: runVerify},
+	"lint":   {summary: "audit stored varsigs for weak or non-canonical headers", run: runLint},
+}
+
+// commandOrder is the order in which commands are listed in the usage.
+var commandOrder = []string{"decode", "encode", "sign", "verify", "lint"}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "varsig: unknown command %q\n\n", args[0])
+		usage(stderr)
+
+		return exitUsage
+	}
+
+	return cmd.run(args[1:], stdin, stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: varsig <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "varsig <command> -h" for the command's flags.`)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs the varsig command and returns its exit code, stdout
+// and stderr.
+func runCommand(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no command", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, stderr := runCommand(t, "")
+		require.Equal(t, exitUsage, code)
+		require.Contains(t, stderr, "Usage: varsig <command>")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, stderr := runCommand(t, "", "frobnicate")
+		require.Equal(t, exitUsage, code)
+		require.Contains(t, stderr, `unknown command "frobnicate"`)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes - all input formats", func(t *testing.T) {
+		t.Parallel()
+
+		for _, arg := range []string{
+			"3401ed01ed011371",
+			"0x3401ed01ed011371",
+			"NAHtAe0BE3E",
+			"NAHtAe0BE3E=",
+			"uNAHtAe0BE3E",
+			"z9hYDEAqjN5e",
+			"f3401ed01ed011371",
+			"bgqa62apnaejxc",
+		} {
+			code, stdout, stderr := runCommand(t, "", "decode", arg)
+			require.Equal(t, exitOK, code, stderr)
+			require.Contains(t, stdout, "varsig:           EdDSA/Ed25519/sha2-512/dag-cbor v1")
+			require.Contains(t, stdout, "curve:            Ed25519 (0xed)")
+			require.Contains(t, stdout, "payload-encoding: dag-cbor (0x71)")
+		}
+	})
+
+	t.Run("passes - stdin", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "NAHtAe0BE3E\n\n3401852412800271\n", "decode", "-json")
+		require.Equal(t, exitOK, code)
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		require.Len(t, lines, 2)
+
+		var res decodeResult
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &res))
+		require.Equal(t, "RSA/sha2-256/2048/dag-cbor v1", res.Varsig)
+		require.Equal(t, "key-length", res.Parameters[3].Name)
+		require.Equal(t, uint64(256), res.Parameters[3].Value)
+	})
+
+	t.Run("passes - raw stdin", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "\x34\x01\xed\x01\xed\x01\x13\x71", "decode", "-format", "raw")
+		require.Equal(t, exitOK, code)
+		require.Contains(t, stdout, "EdDSA/Ed25519/sha2-512/dag-cbor v1")
+	})
+
+	t.Run("fails - reports the error", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "", "decode", "NAHtAe0BE3E", "3401ec01e7011b42")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stdout, "EdDSA/Ed25519/sha2-512/dag-cbor v1")
+		require.Contains(t, stdout, "error: payload-encoding at offset 7: unsupported payload encoding: encoding=42")
+	})
+
+	t.Run("fails - trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "", "decode", "3401ed01ed011371dead")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stdout, "varsig:           EdDSA/Ed25519/sha2-512/dag-cbor v1")
+		require.Contains(t, stdout, "trailing:         2 bytes follow the varsig (dead)")
+
+		code, stdout, _ = runCommand(t, "", "decode", "-json", "3401ed01ed011371dead")
+		require.Equal(t, exitFailure, code)
+
+		var res decodeResult
+		require.NoError(t, json.Unmarshal([]byte(stdout), &res))
+		require.Equal(t, "dead", res.Trailing)
+		require.Empty(t, res.Error)
+	})
+
+	t.Run("fails - unrecognized input", func(t *testing.T) {
+		t.Parallel()
+
+		code, stdout, _ := runCommand(t, "", "decode", "-json", "not a varsig!")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stdout, `"error":"unrecognized input format`)
+	})
+
+	t.Run("fails - bad flag", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, _ := runCommand(t, "", "decode", "-nope")
+		require.Equal(t, exitUsage, code)
+	})
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			args []string
+			want string
+		}{
+			{args: []string{"-alg", "Ed25519"}, want: "3401ed01ed011371"},
+			{args: []string{"-alg", "EdDSA"}, want: "3401ed01ed011371"},
+			{args: []string{"--alg", "es256", "--payload", "verbatim"}, want: "3401ec018024125f"},
+			{args: []string{"-alg", "RS256", "-rsa-keylen", "256", "-output", "base64"}, want: "NAGFJBKAAnE"},
+			{args: []string{"-alg", "EIP191", "-payload", "eip191-cbor"}, want: "3401ec01e7011b91c30371"},
+			{args: []string{"-alg", "EIP712", "-payload", "eip712"}, want: "3401ec01e7011b92ce03"},
+			{args: []string{"-alg", "ES256", "-payload", "webauthn-cbor"}, want: "3401ec018024128080c00171"},
+			{args: []string{"-descriptor", "eddsa:ed25519:sha2-512:dag-cbor", "-output", "multibase"}, want: "uNAHtAe0BE3E"},
+			{args: []string{"-descriptor", "rsa:sha2-256:2048:dag-cbor", "-output", "multibase", "-base", "base58btc"}, want: "z9hXKWGDLcKS"},
+		} {
+			t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+				t.Parallel()
+
+				code, stdout, stderr := runCommand(t, "", append([]string{"encode"}, tc.args...)...)
+				require.Equal(t, exitOK, code, stderr)
+				require.Equal(t, tc.want+"\n", stdout)
+
+				// What's encoded can be decoded.
+				code, _, _ = runCommand(t, "", "decode", strings.TrimSpace(stdout))
+				require.Equal(t, exitOK, code)
+			})
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			args []string
+			code int
+			err  string
+		}{
+			{args: []string{}, code: exitUsage, err: "exactly one of -alg or -descriptor"},
+			{args: []string{"-alg", "ES256", "-descriptor", "eddsa:ed25519:sha2-512:dag-cbor"}, code: exitUsage, err: "exactly one of"},
+			{args: []string{"-alg", "PS256"}, code: exitFailure, err: `unknown algorithm "PS256"`},
+			{args: []string{"-alg", "RS256"}, code: exitFailure, err: "-rsa-keylen is required"},
+			{args: []string{"-alg", "ES256", "-payload", "cbor"}, code: exitFailure, err: "unsupported payload encoding"},
+			{args: []string{"-alg", "EIP191"}, code: exitFailure, err: "unsupported payload encoding for EIP191"},
+			{args: []string{"-alg", "ES256", "-output", "base2"}, code: exitFailure, err: `unknown output format "base2"`},
+			{args: []string{"-alg", "ES256", "-output", "multibase", "-base", "base2"}, code: exitFailure, err: `unknown multibase base "base2"`},
+			{args: []string{"-descriptor", "rsa:sha2-256"}, code: exitFailure, err: "malformed varsig descriptor"},
+			{args: []string{"-alg", "ES256", "-payload", "unspecified"}, code: exitFailure, err: "unsupported payload encoding: unspecified"},
+			{args: []string{"-descriptor", "ecdsa:p-256:sha2-256:unspecified"}, code: exitFailure, err: "unsupported payload encoding: unspecified"},
+			{args: []string{"-descriptor", "ecdsa:ed25519:sha2-256:dag-cbor"}, code: exitFailure, err: "unknown ECDSA curve: ed25519"},
+			{args: []string{"-descriptor", "eddsa:p-256:sha2-512:dag-cbor"}, code: exitFailure, err: "unknown Edwards curve: p-256"},
+			{args: []string{"-alg", "ES256", "-payload", "jwt"}, code: exitFailure, err: "refusing to encode ECDSA/P-256/sha2-256/jwt v1"},
+			{args: []string{"-descriptor", "eddsa:ed25519:sha2-512:dag-pb"}, code: exitFailure, err: "which can't be decoded"},
+			{args: []string{"-alg", "PS256", "-output", "base2"}, code: exitFailure, err: `unknown output format "base2"`},
+		} {
+			t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+				t.Parallel()
+
+				code, stdout, stderr := runCommand(t, "", append([]string{"encode"}, tc.args...)...)
+				require.Equal(t, tc.code, code)
+				require.Empty(t, stdout)
+				require.Contains(t, stderr, tc.err)
+			})
+		}
+	})
+}
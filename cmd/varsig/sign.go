@@ -0,0 +1,410 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers crypto.SHA224 and crypto.SHA256
+	_ "crypto/sha512" // registers crypto.SHA384, crypto.SHA512, crypto.SHA512_224 and crypto.SHA512_256
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+var (
+	errKeyMismatch      = errors.New("key doesn't match the varsig")
+	errUnsupportedSig   = errors.New("unsupported by this tool")
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+var cryptoHashes = map[varsig.Hash]crypto.Hash{
+	varsig.HashSha2_224:   crypto.SHA224,
+	varsig.HashSha2_256:   crypto.SHA256,
+	varsig.HashSha2_384:   crypto.SHA384,
+	varsig.HashSha2_512:   crypto.SHA512,
+	varsig.HashSha512_224: crypto.SHA512_224,
+	varsig.HashSha512_256: crypto.SHA512_256,
+}
+
+var ecdsaCurves = map[varsig.ECDSACurve]elliptic.Curve{
+	varsig.CurveP256: elliptic.P256(),
+	varsig.CurveP384: elliptic.P384(),
+	varsig.CurveP521: elliptic.P521(),
+}
+
+// ecdsaHashes are the hash algorithms used with each curve by the JOSE
+// algorithms (ES256, ES384 and ES512.)
+var ecdsaHashes = map[varsig.ECDSACurve]varsig.Hash{
+	varsig.CurveP256: varsig.HashSha2_256,
+	varsig.CurveP384: varsig.HashSha2_384,
+	varsig.CurveP521: varsig.HashSha2_512,
+}
+
+// signFlags are the flags shared by the sign and verify subcommands.
+type signFlags struct {
+	fs          *flag.FlagSet
+	keyPath     *string
+	payloadPath *string
+	varsig      *string
+	payEnc      *string
+}
+
+func newSignFlags(name, description string, stderr io.Writer) signFlags {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: varsig %s [flags]\n\n%s\n\n", name, description)
+		fs.PrintDefaults()
+	}
+
+	return signFlags{
+		fs:          fs,
+		keyPath:     fs.String("key", "", "PEM or JWK key file (required)"),
+		payloadPath: fs.String("payload", "", "payload file, already encoded as described by the varsig (required)"),
+		varsig:      fs.String("varsig", "", "varsig (hex, base64 or multibase); defaults to the usual varsig for the key"),
+		payEnc:      fs.String("payload-encoding", "dag-cbor", "payload encoding of the default varsig"),
+	}
+}
+
+// load parses the flags and returns the key, payload and varsig.
+func (f signFlags) load(args []string, stderr io.Writer) (key, []byte, varsig.Varsig, int) {
+	if err := f.fs.Parse(args); err != nil {
+		return key{}, nil, nil, exitUsage
+	}
+
+	if *f.keyPath == "" || *f.payloadPath == "" || f.fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "varsig: -key and -payload are required")
+		f.fs.Usage()
+
+		return key{}, nil, nil, exitUsage
+	}
+
+	k, err := loadKey(*f.keyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return key{}, nil, nil, exitFailure
+	}
+
+	payload, err := os.ReadFile(*f.payloadPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return key{}, nil, nil, exitFailure
+	}
+
+	vs, err := f.loadVarsig(k)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return key{}, nil, nil, exitFailure
+	}
+
+	if mismatches := checkKey(vs, k.public); len(mismatches) > 0 {
+		fmt.Fprintf(stderr, "varsig: %v (%v):\n", errKeyMismatch, vs)
+
+		for _, m := range mismatches {
+			fmt.Fprintf(stderr, "  - %s\n", m)
+		}
+
+		return key{}, nil, nil, exitFailure
+	}
+
+	return k, payload, vs, exitOK
+}
+
+func (f signFlags) loadVarsig(k key) (varsig.Varsig, error) {
+	if *f.varsig != "" {
+		data, err := parseInput(*f.varsig, formatAuto)
+		if err != nil {
+			return nil, err
+		}
+
+		return varsig.Decode(data, webAuthn)
+	}
+
+	payEnc, err := varsig.ParsePayloadEncoding(*f.payEnc)
+	if err != nil {
+		return nil, err
+	}
+
+	return defaultVarsig(k.public, payEnc)
+}
+
+func runSign(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	f := newSignFlags("sign", "Signs the payload with the private key as described by the varsig and prints the signature.", stderr)
+	output := f.fs.String("output", outputHex, "signature output format: hex, base64 or multibase (base64url)")
+
+	k, payload, vs, code := f.load(args, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	if k.private == nil {
+		fmt.Fprintln(stderr, "varsig: signing requires a private key")
+		return exitFailure
+	}
+
+	sig, err := sign(vs, k.private, payload)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	var text string
+
+	switch *output {
+	case outputHex:
+		text = hex.EncodeToString(sig)
+	case outputBase64:
+		text = base64.RawStdEncoding.EncodeToString(sig)
+	case outputMultibase:
+		// The multibase prefix for unpadded base64url is 'u'.
+		text = "u" + base64.RawURLEncoding.EncodeToString(sig)
+	default:
+		err = fmt.Errorf("unknown output format %q", *output)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	fmt.Fprintln(stdout, text)
+
+	return exitOK
+}
+
+func runVerify(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	f := newSignFlags("verify", "Verifies the signature of the payload with the key as described by the varsig.", stderr)
+	sigText := f.fs.String("signature", "", "signature (hex, base64 or multibase)")
+	sigPath := f.fs.String("signature-file", "", "file containing the raw signature, instead of -signature")
+
+	k, payload, vs, code := f.load(args, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	sig, err := readSignature(*sigText, *sigPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitUsage
+	}
+
+	if err := verify(vs, k.public, payload, sig); err != nil {
+		fmt.Fprintf(stderr, "varsig: %v\n", err)
+		return exitFailure
+	}
+
+	fmt.Fprintf(stdout, "valid signature (%v)\n", vs)
+
+	return exitOK
+}
+
+func readSignature(text, path string) ([]byte, error) {
+	switch {
+	case (text == "") == (path == ""):
+		return nil, errors.New("exactly one of -signature or -signature-file is required")
+	case path != "":
+		return os.ReadFile(path) // #nosec G304 -- the path is provided by the user
+	default:
+		// Signatures don't start with the varsig prefix, so the automatic
+		// detection of hex and multibase doesn't apply.
+		for _, format := range []string{formatHex, formatMultibase, formatBase64} {
+			if sig, err := parseInput(text, format); err == nil {
+				return sig, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %q", errUnrecognizedInput, text)
+	}
+}
+
+// defaultVarsig returns the usual varsig for signatures produced by the
+// key.
+func defaultVarsig(pub crypto.PublicKey, payEnc varsig.PayloadEncoding) (varsig.Varsig, error) {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return varsig.Ed25519(payEnc), nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return varsig.ES256(payEnc), nil
+		case elliptic.P384():
+			return varsig.ES384(payEnc), nil
+		default:
+			return varsig.ES512(payEnc), nil
+		}
+	case *rsa.PublicKey:
+		return varsig.RS256(uint64(pub.Size()), payEnc), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupportedKey, pub)
+	}
+}
+
+// checkKey returns the differences between the key and the key described
+// by the varsig.
+func checkKey(vs varsig.Varsig, pub crypto.PublicKey) []string {
+	var mismatches []string
+
+	switch vs := vs.(type) {
+	case varsig.EdDSAVarsig:
+		if _, ok := pub.(ed25519.PublicKey); !ok {
+			mismatches = append(mismatches, fmt.Sprintf("varsig curve is %v but the key is %s", vs.Curve(), describeKey(pub)))
+		} else if vs.Curve() != varsig.CurveEd25519 {
+			mismatches = append(mismatches, fmt.Sprintf("varsig curve is %v but the key is Ed25519", vs.Curve()))
+		}
+	case varsig.ECDSAVarsig:
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok || ecdsaCurves[vs.Curve()] != ecPub.Curve {
+			mismatches = append(mismatches, fmt.Sprintf("varsig curve is %v but the key is %s", vs.Curve(), describeKey(pub)))
+		}
+	case varsig.RSAVarsig:
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("varsig algorithm is RSA but the key is %s", describeKey(pub)))
+		} else if uint64(rsaPub.Size()) != vs.KeyLength() {
+			mismatches = append(mismatches, fmt.Sprintf("varsig key length is %d bytes but the key is %s", vs.KeyLength(), describeKey(pub)))
+		}
+	default:
+		mismatches = append(mismatches, fmt.Sprintf("varsig algorithm %v is %v", vs.Algorithm(), errUnsupportedSig))
+	}
+
+	switch vs := vs.(type) {
+	case varsig.EdDSAVarsig:
+		if vs.Curve() == varsig.CurveEd25519 && vs.Hash() != varsig.HashSha2_512 {
+			mismatches = append(mismatches, fmt.Sprintf("varsig hash is %v but Ed25519 uses sha2-512", vs.Hash()))
+		}
+	case varsig.ECDSAVarsig:
+		if h, ok := ecdsaHashes[vs.Curve()]; ok && vs.Hash() != h {
+			mismatches = append(mismatches, fmt.Sprintf("varsig hash is %v but %v uses %v", vs.Hash(), vs.Curve(), h))
+		}
+	}
+
+	return mismatches
+}
+
+func describeKey(pub crypto.PublicKey) string {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return "Ed25519"
+	case *ecdsa.PublicKey:
+		return "ECDSA " + pub.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA with a %d byte modulus", pub.Size())
+	default:
+		return fmt.Sprintf("%T", pub)
+	}
+}
+
+// signedData returns the bytes that are signed for the payload.
+func signedData(vs varsig.Varsig, payload []byte) ([]byte, error) {
+	switch vs.PayloadEncoding() {
+	case varsig.PayloadEncodingVerbatim, varsig.PayloadEncodingDAGPB, varsig.PayloadEncodingDAGCBOR,
+		varsig.PayloadEncodingDAGJSON, varsig.PayloadEncodingJWT:
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("payload encoding %v is %w", vs.PayloadEncoding(), errUnsupportedSig)
+	}
+}
+
+// digest returns the hash of the data using the varsig's hash algorithm.
+func digest(vs varsig.Varsig, data []byte) (crypto.Hash, []byte, error) {
+	h, ok := cryptoHashes[vs.Hash()]
+	if !ok {
+		return 0, nil, fmt.Errorf("hash %v is %w", vs.Hash(), errUnsupportedSig)
+	}
+
+	hasher := h.New()
+	_, _ = hasher.Write(data)
+
+	return h, hasher.Sum(nil), nil
+}
+
+func sign(vs varsig.Varsig, signer crypto.Signer, payload []byte) ([]byte, error) {
+	data, err := signedData(vs, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if priv, ok := signer.(ed25519.PrivateKey); ok {
+		return ed25519.Sign(priv, data), nil
+	}
+
+	h, sum, err := digest(vs, data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch priv := signer.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, priv, sum)
+		if err != nil {
+			return nil, err
+		}
+
+		// Like JWS, the signature is the fixed size concatenation of r and s.
+		size := (priv.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+
+		return sig, nil
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, priv, h, sum)
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupportedKey, signer)
+	}
+}
+
+func verify(vs varsig.Varsig, pub crypto.PublicKey, payload, sig []byte) error {
+	data, err := signedData(vs, payload)
+	if err != nil {
+		return err
+	}
+
+	if pub, ok := pub.(ed25519.PublicKey); ok {
+		if !ed25519.Verify(pub, data, sig) {
+			return errInvalidSignature
+		}
+
+		return nil
+	}
+
+	h, sum, err := digest(vs, data)
+	if err != nil {
+		return err
+	}
+
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return fmt.Errorf("%w: expected %d bytes, got %d", errInvalidSignature, 2*size, len(sig))
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+
+		if !ecdsa.Verify(pub, sum, r, s) {
+			return errInvalidSignature
+		}
+
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, h, sum, sig); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidSignature, err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedKey, pub)
+	}
+}
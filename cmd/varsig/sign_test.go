@@ -0,0 +1,217 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	payload := writeFile(t, dir, "payload.cbor", []byte("\xa1\x61\x61\x01"))
+	tampered := writeFile(t, dir, "tampered.cbor", []byte("\xa1\x61\x61\x02"))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	ecPath := writeFile(t, dir, "p256.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}))
+
+	ecPubDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+	ecPubPath := writeFile(t, dir, "p256.pub.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecPubDER}))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edJWK, err := json.Marshal(map[string]string{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"x":   base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)),
+		"d":   base64.RawURLEncoding.EncodeToString(edKey.Seed()),
+	})
+	require.NoError(t, err)
+	edPath := writeFile(t, dir, "ed25519.jwk", edJWK)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPath := writeFile(t, dir, "rsa.pem", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	rsaJWK, err := json.Marshal(map[string]string{
+		"kty": "RSA",
+		"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		"e":   "AQAB",
+		"d":   base64.RawURLEncoding.EncodeToString(rsaKey.D.Bytes()),
+		"p":   base64.RawURLEncoding.EncodeToString(rsaKey.Primes[0].Bytes()),
+		"q":   base64.RawURLEncoding.EncodeToString(rsaKey.Primes[1].Bytes()),
+	})
+	require.NoError(t, err)
+	rsaJWKPath := writeFile(t, dir, "rsa.jwk", rsaJWK)
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name      string
+			signKey   string
+			verifyKey string
+			args      []string
+			varsig    string
+		}{
+			{name: "ES256 default varsig", signKey: ecPath, verifyKey: ecPubPath, varsig: "ECDSA/P-256/sha2-256/dag-cbor v1"},
+			{name: "ES256 explicit varsig", signKey: ecPath, verifyKey: ecPubPath, args: []string{"-varsig", "3401ec0180241271"}, varsig: "ECDSA/P-256/sha2-256/dag-cbor v1"},
+			{name: "Ed25519 JWK", signKey: edPath, verifyKey: edPath, args: []string{"-payload-encoding", "verbatim"}, varsig: "EdDSA/Ed25519/sha2-512/verbatim v1"},
+			{name: "RS256 PEM and JWK", signKey: rsaPath, verifyKey: rsaJWKPath, varsig: "RSA/sha2-256/2048/dag-cbor v1"},
+			{name: "RS512", signKey: rsaJWKPath, verifyKey: rsaPath, args: []string{"-varsig", "uNAGFJBOAAnE"}, varsig: "RSA/sha2-512/2048/dag-cbor v1"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				args := append([]string{"sign", "-key", tc.signKey, "-payload", payload}, tc.args...)
+				code, sig, stderr := runCommand(t, "", args...)
+				require.Equal(t, exitOK, code, stderr)
+
+				args = append([]string{"verify", "-key", tc.verifyKey, "-payload", payload, "-signature", strings.TrimSpace(sig)}, tc.args...)
+				code, stdout, stderr := runCommand(t, "", args...)
+				require.Equal(t, exitOK, code, stderr)
+				require.Equal(t, "valid signature ("+tc.varsig+")\n", stdout)
+
+				args = append([]string{"verify", "-key", tc.verifyKey, "-payload", tampered, "-signature", strings.TrimSpace(sig)}, tc.args...)
+				code, _, stderr = runCommand(t, "", args...)
+				require.Equal(t, exitFailure, code)
+				require.Contains(t, stderr, "invalid signature")
+			})
+		}
+	})
+
+	t.Run("passes - signature file and base64 output", func(t *testing.T) {
+		t.Parallel()
+
+		code, sig, _ := runCommand(t, "", "sign", "-key", edPath, "-payload", payload, "-output", "base64")
+		require.Equal(t, exitOK, code)
+
+		raw, err := base64.RawStdEncoding.DecodeString(strings.TrimSpace(sig))
+		require.NoError(t, err)
+		sigPath := writeFile(t, t.TempDir(), "sig", raw)
+
+		code, _, stderr := runCommand(t, "", "verify", "-key", edPath, "-payload", payload, "-signature-file", sigPath)
+		require.Equal(t, exitOK, code, stderr)
+	})
+
+	t.Run("fails - key mismatches", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name    string
+			key     string
+			varsig  string
+			message string
+		}{
+			{name: "curve", key: ecPath, varsig: "3401ec0181242071", message: "varsig curve is P-384 but the key is ECDSA P-256"},
+			{name: "algorithm", key: edPath, varsig: "3401ec0180241271", message: "varsig curve is P-256 but the key is Ed25519"},
+			{name: "Ed25519 hash", key: edPath, varsig: "3401ed01ed011271", message: "varsig hash is sha2-256 but Ed25519 uses sha2-512"},
+			{name: "ECDSA hash", key: ecPath, varsig: "3401ec0180241371", message: "varsig hash is sha2-512 but P-256 uses sha2-256"},
+			{name: "RSA key length", key: rsaPath, varsig: "3401852412800471", message: "varsig key length is 512 bytes but the key is RSA with a 256 byte modulus"},
+			{name: "RSA algorithm", key: ecPath, varsig: "3401852412800271", message: "varsig algorithm is RSA but the key is ECDSA P-256"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				code, stdout, stderr := runCommand(t, "", "sign", "-key", tc.key, "-payload", payload, "-varsig", tc.varsig)
+				require.Equal(t, exitFailure, code)
+				require.Empty(t, stdout)
+				require.Contains(t, stderr, "key doesn't match the varsig")
+				require.Contains(t, stderr, tc.message)
+			})
+		}
+	})
+
+	t.Run("fails - JWK private key mismatches", func(t *testing.T) {
+		t.Parallel()
+
+		otherEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		_, otherEd, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			name string
+			jwk  map[string]string
+		}{
+			{name: "EC", jwk: map[string]string{
+				"kty": "EC",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+				"d":   base64.RawURLEncoding.EncodeToString(otherEC.D.FillBytes(make([]byte, 32))),
+			}},
+			{name: "OKP", jwk: map[string]string{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"x":   base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)),
+				"d":   base64.RawURLEncoding.EncodeToString(otherEd.Seed()),
+			}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data, err := json.Marshal(tc.jwk)
+				require.NoError(t, err)
+				path := writeFile(t, t.TempDir(), "key.jwk", data)
+
+				code, stdout, stderr := runCommand(t, "", "sign", "-key", path, "-payload", payload)
+				require.Equal(t, exitFailure, code)
+				require.Empty(t, stdout)
+				require.Contains(t, stderr, "JWK private key doesn't match the public key")
+			})
+		}
+	})
+
+	t.Run("fails - unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, stderr := runCommand(t, "", "sign", "-key", rsaPath, "-payload", payload, "-varsig", "340185241b800271")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stderr, "hash keccak-256 is unsupported by this tool")
+
+		code, _, stderr = runCommand(t, "", "sign", "-key", ecPath, "-payload", payload, "-payload-encoding", "eip191-raw")
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stderr, "payload encoding eip191-raw is unsupported by this tool")
+
+		code, _, stderr = runCommand(t, "", "sign", "-key", ecPubPath, "-payload", payload)
+		require.Equal(t, exitFailure, code)
+		require.Contains(t, stderr, "signing requires a private key")
+	})
+
+	t.Run("fails - usage", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, _ := runCommand(t, "", "sign", "-key", ecPath)
+		require.Equal(t, exitUsage, code)
+
+		code, _, stderr := runCommand(t, "", "verify", "-key", ecPath, "-payload", payload)
+		require.Equal(t, exitUsage, code)
+		require.Contains(t, stderr, "exactly one of -signature or -signature-file")
+	})
+}
@@ -71,3 +71,32 @@ func EIP191(payloadEncoding PayloadEncoding) (ECDSAVarsig, error) {
 
 	return NewECDSAVarsig(CurveSecp256k1, HashKeccak_256, payloadEncoding), nil
 }
+
+// EIP712 produces a varsig for ECDSA using the Secp256k1 curve, Keccak256 and the
+// typed structured data signed by "eth_signTypedData_v4" as defined by [EIP712].
+// [EIP712]: https://eips.ethereum.org/EIPS/eip-712
+func EIP712() ECDSAVarsig {
+	return NewECDSAVarsig(CurveSecp256k1, HashKeccak_256, PayloadEncodingEIP712)
+}
+
+// WebAuthnES256 produces a varsig for ECDSA using P-256 and SHA-256 where
+// the payload is signed by a WebAuthn authenticator (passkey.)
+// payloadEncoding must be either PayloadEncodingWebAuthnRaw or PayloadEncodingWebAuthnCbor.
+func WebAuthnES256(payloadEncoding PayloadEncoding) (ECDSAVarsig, error) {
+	if !isWebAuthnPayloadEncoding(payloadEncoding) {
+		return ECDSAVarsig{}, fmt.Errorf("%w for WebAuthn: %v", ErrUnsupportedPayloadEncoding, payloadEncoding)
+	}
+
+	return NewECDSAVarsig(CurveP256, HashSha2_256, payloadEncoding), nil
+}
+
+// WebAuthnEd25519 produces a varsig for EdDSA using the Ed25519 curve where
+// the payload is signed by a WebAuthn authenticator (passkey.)
+// payloadEncoding must be either PayloadEncodingWebAuthnRaw or PayloadEncodingWebAuthnCbor.
+func WebAuthnEd25519(payloadEncoding PayloadEncoding) (EdDSAVarsig, error) {
+	if !isWebAuthnPayloadEncoding(payloadEncoding) {
+		return EdDSAVarsig{}, fmt.Errorf("%w for WebAuthn: %v", ErrUnsupportedPayloadEncoding, payloadEncoding)
+	}
+
+	return NewEdDSAVarsig(CurveEd25519, HashSha2_512, payloadEncoding), nil
+}
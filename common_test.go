@@ -2,6 +2,7 @@ package varsig_test
 
 import (
 	"encoding/hex"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -67,6 +68,21 @@ func TestRoundTrip(t *testing.T) {
 			varsig:  must(varsig.EIP191(varsig.PayloadEncodingEIP191Raw)),
 			dataHex: "3401ec01e7011b91c3035f",
 		},
+		{
+			name:    "EIP712",
+			varsig:  varsig.EIP712(),
+			dataHex: "3401ec01e7011b92ce03",
+		},
+		{
+			name:    "WebAuthnES256",
+			varsig:  must(varsig.WebAuthnES256(varsig.PayloadEncodingWebAuthnCbor)),
+			dataHex: "3401ec018024128080c00171",
+		},
+		{
+			name:    "WebAuthnEd25519",
+			varsig:  must(varsig.WebAuthnEd25519(varsig.PayloadEncodingWebAuthnRaw)),
+			dataHex: "3401ed01ed01138080c0015f",
+		},
 
 		// from https://github.com/hugomrdias/iso-repo/blob/main/packages/iso-ucan/test/varsig.test.js
 		{
@@ -111,7 +127,7 @@ func TestRoundTrip(t *testing.T) {
 				require.Equal(t, tc.dataHex, hex.EncodeToString(data))
 			}
 
-			rt, err := varsig.Decode(data)
+			rt, err := varsig.Decode(data, varsig.WithWebAuthn())
 			require.NoError(t, err)
 
 			require.Equal(t, tc.varsig.Version(), rt.Version())
@@ -138,6 +154,39 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestVarsigStrings(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		varsig   varsig.Varsig
+		str      string
+		goString string
+	}{
+		{
+			varsig:   varsig.Ed25519(varsig.PayloadEncodingDAGCBOR),
+			str:      "EdDSA/Ed25519/sha2-512/dag-cbor v1",
+			goString: "varsig.NewEdDSAVarsig(varsig.CurveEd25519, varsig.HashSha2_512, varsig.PayloadEncodingDAGCBOR)",
+		},
+		{
+			varsig:   varsig.ES256K(varsig.PayloadEncodingVerbatim),
+			str:      "ECDSA/secp256k1/sha2-256/verbatim v1",
+			goString: "varsig.NewECDSAVarsig(varsig.CurveSecp256k1, varsig.HashSha2_256, varsig.PayloadEncodingVerbatim)",
+		},
+		{
+			varsig:   varsig.RS256(0x100, varsig.PayloadEncodingDAGJSON),
+			str:      "RSA/sha2-256/2048/dag-json v1",
+			goString: "varsig.NewRSAVarsig(varsig.HashSha2_256, 256, varsig.PayloadEncodingDAGJSON)",
+		},
+	} {
+		t.Run(tc.str, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.str, fmt.Sprint(tc.varsig))
+			require.Equal(t, tc.goString, fmt.Sprintf("%#v", tc.varsig))
+		})
+	}
+}
+
 func must[T any](v T, err error) T {
 	if err != nil {
 		panic(err)
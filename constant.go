@@ -47,17 +47,73 @@ const (
 	HashSha1       = Hash(0x11)
 )
 
+var hashNames = map[Hash]constantName{
+	HashUnspecified: {"unspecified", "HashUnspecified"},
+	HashSha2_224:    {"sha2-224", "HashSha2_224"},
+	HashSha2_256:    {"sha2-256", "HashSha2_256"},
+	HashSha2_384:    {"sha2-384", "HashSha2_384"},
+	HashSha2_512:    {"sha2-512", "HashSha2_512"},
+	HashSha3_224:    {"sha3-224", "HashSha3_224"},
+	HashSha3_256:    {"sha3-256", "HashSha3_256"},
+	HashSha3_384:    {"sha3-384", "HashSha3_384"},
+	HashSha3_512:    {"sha3-512", "HashSha3_512"},
+	HashSha512_224:  {"sha2-512-224", "HashSha512_224"},
+	HashSha512_256:  {"sha2-512-256", "HashSha512_256"},
+	HashBlake2s_256: {"blake2s-256", "HashBlake2s_256"},
+	HashBlake2b_256: {"blake2b-256", "HashBlake2b_256"},
+	HashBlake2b_384: {"blake2b-384", "HashBlake2b_384"},
+	HashBlake2b_512: {"blake2b-512", "HashBlake2b_512"},
+	HashShake_256:   {"shake-256", "HashShake_256"},
+	HashKeccak_256:  {"keccak-256", "HashKeccak_256"},
+	HashKeccak_512:  {"keccak-512", "HashKeccak_512"},
+	HashRipemd_160:  {"ripemd-160", "HashRipemd_160"},
+	HashMd4:         {"md4", "HashMd4"},
+	HashMd5:         {"md5", "HashMd5"},
+	HashSha1:        {"sha1", "HashSha1"},
+}
+
+// String returns the multicodec name of the hash algorithm.
+func (h Hash) String() string {
+	return formatConstant(hashNames, h)
+}
+
+// GoString returns the Go syntax representation of the hash algorithm.
+func (h Hash) GoString() string {
+	return goStringConstant(hashNames, h, "Hash")
+}
+
 // DecodeHashAlgorithm reads and validates the expected hash algorithm
-// (for varsig types include a variable hash algorithm.)
+// (for varsig types include a variable hash algorithm.)  The offsets of
+// the DecodeErrors it returns are counted from the start of the varsig
+// when r is the reader passed to a DecodeFunc, and from the first byte
+// read by DecodeHashAlgorithm otherwise.
 func DecodeHashAlgorithm(r BytesReader) (Hash, error) {
-	u, err := binary.ReadUvarint(r)
+	return readerOf(r).hash()
+}
+
+func (d *decodeReader) hash() (Hash, error) {
+	off := d.offset
+
+	u, err := d.readUvarint()
+	if err != nil {
+		return HashUnspecified, decodeError(ParameterHash, off, 0, fmt.Errorf("%w: %w", ErrUnknownHash, err))
+	}
+
+	if err := d.checkLimit(ParameterHash, off, u); err != nil {
+		return HashUnspecified, err
+	}
+
+	h, err := hashFor(u)
 	if err != nil {
-		return HashUnspecified, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+		return HashUnspecified, decodeError(ParameterHash, off, u, err)
 	}
 
-	h := Hash(u)
+	return h, nil
+}
 
-	switch h {
+// hashFor validates the decoded hash algorithm.
+func hashFor(u uint64) (Hash, error) {
+	switch h := Hash(u); h {
 	case HashSha2_224,
 		HashSha2_256,
 		HashSha2_384,
@@ -81,7 +137,7 @@ func DecodeHashAlgorithm(r BytesReader) (Hash, error) {
 		HashSha1:
 		return h, nil
 	default:
-		return HashUnspecified, fmt.Errorf("%w: %x", ErrUnknownHash, h)
+		return HashUnspecified, fmt.Errorf("%w: %v", ErrUnknownHash, h)
 	}
 }
 
@@ -101,8 +157,45 @@ const (
 	PayloadEncodingEIP191Raw
 	PayloadEncodingEIP191Cbor
 	PayloadEncodingJWT
+	PayloadEncodingWebAuthnRaw
+	PayloadEncodingWebAuthnCbor
+	PayloadEncodingEIP712
 )
 
+var payloadEncodingNames = map[PayloadEncoding]constantName{
+	PayloadEncodingUnspecified:  {"unspecified", "PayloadEncodingUnspecified"},
+	PayloadEncodingVerbatim:     {"verbatim", "PayloadEncodingVerbatim"},
+	PayloadEncodingDAGPB:        {"dag-pb", "PayloadEncodingDAGPB"},
+	PayloadEncodingDAGCBOR:      {"dag-cbor", "PayloadEncodingDAGCBOR"},
+	PayloadEncodingDAGJSON:      {"dag-json", "PayloadEncodingDAGJSON"},
+	PayloadEncodingEIP191Raw:    {"eip191-raw", "PayloadEncodingEIP191Raw"},
+	PayloadEncodingEIP191Cbor:   {"eip191-cbor", "PayloadEncodingEIP191Cbor"},
+	PayloadEncodingJWT:          {"jwt", "PayloadEncodingJWT"},
+	PayloadEncodingWebAuthnRaw:  {"webauthn-raw", "PayloadEncodingWebAuthnRaw"},
+	PayloadEncodingWebAuthnCbor: {"webauthn-cbor", "PayloadEncodingWebAuthnCbor"},
+	PayloadEncodingEIP712:       {"eip712", "PayloadEncodingEIP712"},
+}
+
+// String returns the name of the payload encoding.  Unlike the other
+// constants, payload encodings aren't multicodec values, so unknown ones
+// are formatted as "PayloadEncoding(N)".
+func (enc PayloadEncoding) String() string {
+	if name, ok := payloadEncodingNames[enc]; ok {
+		return name.text
+	}
+
+	return fmt.Sprintf("PayloadEncoding(%d)", int(enc))
+}
+
+// GoString returns the Go syntax representation of the payload encoding.
+func (enc PayloadEncoding) GoString() string {
+	if _, ok := payloadEncodingNames[enc]; !ok {
+		return "varsig." + enc.String()
+	}
+
+	return goStringConstant(payloadEncodingNames, enc, "PayloadEncoding")
+}
+
 const (
 	encodingSegmentVerbatim = uint64(0x5f)
 	encodingSegmentDAGPB    = uint64(0x70)
@@ -110,16 +203,81 @@ const (
 	encodingSegmentDAGJSON  = uint64(0x0129)
 	encodingSegmentEIP191   = uint64(0xe191)
 	encodingSegmentJWT      = uint64(0x6a77)
+
+	// No multicodec is registered for EIP-712 yet, so 0xe712 is used
+	// until one is.  Other implementations may not recognize it.
+	encodingSegmentEIP712 = uint64(0xe712)
+
+	// No multicodec is registered for WebAuthn yet, so a value from the
+	// private use range is used until one is.  As other applications may
+	// use the same value, it's only decoded with the WithWebAuthn option.
+	encodingSegmentWebAuthn = uint64(0x300000)
 )
 
 // DecodePayloadEncoding reads and validates the expected canonical payload
-// encoding of the data to be signed.
+// encoding of the data to be signed.  Like DecodeHashAlgorithm, the offsets
+// of its DecodeErrors are counted from the start of the varsig only when r
+// is the reader passed to a DecodeFunc.
 func DecodePayloadEncoding(r BytesReader) (PayloadEncoding, error) {
-	seg1, err := binary.ReadUvarint(r)
+	return readerOf(r).payloadEncoding()
+}
+
+func (d *decodeReader) payloadEncoding() (PayloadEncoding, error) {
+	off := d.offset
+
+	seg1, err := d.readUvarint()
 	if err != nil {
-		return PayloadEncodingUnspecified, fmt.Errorf("%w: %w", ErrUnsupportedPayloadEncoding, err)
+		return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncoding, off, 0, fmt.Errorf("%w: %w", ErrUnsupportedPayloadEncoding, err))
 	}
 
+	if err := d.checkLimit(ParameterPayloadEncoding, off, seg1); err != nil {
+		return PayloadEncodingUnspecified, err
+	}
+
+	nested, ok := nestedPayloadEncodingFor(seg1)
+	if !ok {
+		if enc, ok := d.payloadEncodings[seg1]; ok {
+			if enc == PayloadEncodingUnspecified {
+				return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncoding, off, seg1, fmt.Errorf("%w: %x", ErrUnsupportedPayloadEncoding, seg1))
+			}
+
+			return enc, nil
+		}
+
+		enc, err := payloadEncodingFor(seg1)
+		if err != nil {
+			return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncoding, off, seg1, err)
+		}
+
+		return enc, nil
+	}
+
+	if seg1 == encodingSegmentWebAuthn && !d.webAuthn {
+		return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncoding, off, seg1, fmt.Errorf("%w: WebAuthn uses the private-use multicodec 0x%x (see WithWebAuthn)", ErrUnsupportedPayloadEncoding, seg1))
+	}
+
+	off = d.offset
+
+	seg2, err := d.readUvarint()
+	if err != nil {
+		return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncodingInner, off, 0, nested.incomplete(err))
+	}
+
+	if err := d.checkLimit(ParameterPayloadEncodingInner, off, seg2); err != nil {
+		return PayloadEncodingUnspecified, err
+	}
+
+	enc, err := nested.payloadEncodingFor(seg1, seg2)
+	if err != nil {
+		return PayloadEncodingUnspecified, decodeError(ParameterPayloadEncodingInner, off, seg2, err)
+	}
+
+	return enc, nil
+}
+
+// payloadEncodingFor returns the PayloadEncoding made of the single
+// segment seg1.
+func payloadEncodingFor(seg1 uint64) (PayloadEncoding, error) {
 	switch seg1 {
 	case encodingSegmentVerbatim:
 		return PayloadEncodingVerbatim, nil
@@ -127,50 +285,118 @@ func DecodePayloadEncoding(r BytesReader) (PayloadEncoding, error) {
 		return PayloadEncodingDAGCBOR, nil
 	case encodingSegmentDAGJSON:
 		return PayloadEncodingDAGJSON, nil
-	case encodingSegmentEIP191:
-		seg2, err := binary.ReadUvarint(r)
-		if err != nil {
-			return PayloadEncodingUnspecified, fmt.Errorf("%w: incomplete EIP191 encoding: %w", ErrUnsupportedPayloadEncoding, err)
-		}
-		switch seg2 {
-		case encodingSegmentVerbatim:
-			return PayloadEncodingEIP191Raw, nil
-		case encodingSegmentDAGCBOR:
-			return PayloadEncodingEIP191Cbor, nil
-		default:
-			return PayloadEncodingUnspecified, fmt.Errorf("%w: encoding=%x+%x", ErrUnsupportedPayloadEncoding, seg1, seg2)
-		}
+	case encodingSegmentEIP712:
+		return PayloadEncodingEIP712, nil
 	default:
 		return PayloadEncodingUnspecified, fmt.Errorf("%w: encoding=%x", ErrUnsupportedPayloadEncoding, seg1)
 	}
 }
 
+// nestedPayloadEncoding describes payload encodings (such as EIP191)
+// whose second segment specifies whether they wrap a verbatim or DAG-CBOR
+// encoded payload.
+type nestedPayloadEncoding struct {
+	name      string
+	raw, cbor PayloadEncoding
+}
+
+// nestedPayloadEncodingFor returns the nested payload encoding whose
+// first segment is seg1, if there's one.
+func nestedPayloadEncodingFor(seg1 uint64) (nestedPayloadEncoding, bool) {
+	switch seg1 {
+	case encodingSegmentEIP191:
+		return nestedPayloadEncoding{"EIP191", PayloadEncodingEIP191Raw, PayloadEncodingEIP191Cbor}, true
+	case encodingSegmentWebAuthn:
+		return nestedPayloadEncoding{"WebAuthn", PayloadEncodingWebAuthnRaw, PayloadEncodingWebAuthnCbor}, true
+	default:
+		return nestedPayloadEncoding{}, false
+	}
+}
+
+// payloadEncodingFor returns the PayloadEncoding selected by the second
+// segment.
+func (n nestedPayloadEncoding) payloadEncodingFor(seg1, seg2 uint64) (PayloadEncoding, error) {
+	switch seg2 {
+	case encodingSegmentVerbatim:
+		return n.raw, nil
+	case encodingSegmentDAGCBOR:
+		return n.cbor, nil
+	default:
+		return PayloadEncodingUnspecified, fmt.Errorf("%w: encoding=%x+%x", ErrUnsupportedPayloadEncoding, seg1, seg2)
+	}
+}
+
+// incomplete returns the error reported when the second segment can't be
+// read.
+func (n nestedPayloadEncoding) incomplete(err error) error {
+	return fmt.Errorf("%w: incomplete %s encoding: %w", ErrUnsupportedPayloadEncoding, n.name, err)
+}
+
 // EncodePayloadEncoding returns the PayloadEncoding as serialized bytes.
 // If enc is not a valid PayloadEncoding, this function will panic.
 func EncodePayloadEncoding(enc PayloadEncoding) []byte {
-	res := make([]byte, 0, 8)
+	return mustAppendPayloadEncoding(make([]byte, 0, payloadEncodingLen(enc)), enc)
+}
+
+// mustAppendPayloadEncoding appends the serialized PayloadEncoding to buf.
+// If enc is not a valid PayloadEncoding, this function will panic.
+func mustAppendPayloadEncoding(buf []byte, enc PayloadEncoding) []byte {
+	buf, ok := appendPayloadEncoding(buf, enc)
+	if !ok {
+		panic(fmt.Sprintf("invalid encoding: %v", enc))
+	}
+
+	return buf
+}
+
+// appendPayloadEncoding appends the serialized PayloadEncoding to buf.
+// If enc is not a valid PayloadEncoding, buf is returned unchanged along
+// with false.
+func appendPayloadEncoding(buf []byte, enc PayloadEncoding) ([]byte, bool) {
 	switch enc {
 	case PayloadEncodingVerbatim:
-		res = binary.AppendUvarint(res, encodingSegmentVerbatim)
+		buf = binary.AppendUvarint(buf, encodingSegmentVerbatim)
 	case PayloadEncodingDAGPB:
-		res = binary.AppendUvarint(res, encodingSegmentDAGPB)
+		buf = binary.AppendUvarint(buf, encodingSegmentDAGPB)
 	case PayloadEncodingDAGCBOR:
-		res = binary.AppendUvarint(res, encodingSegmentDAGCBOR)
+		buf = binary.AppendUvarint(buf, encodingSegmentDAGCBOR)
 	case PayloadEncodingDAGJSON:
-		res = binary.AppendUvarint(res, encodingSegmentDAGJSON)
+		buf = binary.AppendUvarint(buf, encodingSegmentDAGJSON)
 	case PayloadEncodingEIP191Raw:
-		res = binary.AppendUvarint(res, encodingSegmentEIP191)
-		res = binary.AppendUvarint(res, encodingSegmentVerbatim)
+		buf = binary.AppendUvarint(buf, encodingSegmentEIP191)
+		buf = binary.AppendUvarint(buf, encodingSegmentVerbatim)
 	case PayloadEncodingEIP191Cbor:
-		res = binary.AppendUvarint(res, encodingSegmentEIP191)
-		res = binary.AppendUvarint(res, encodingSegmentDAGCBOR)
+		buf = binary.AppendUvarint(buf, encodingSegmentEIP191)
+		buf = binary.AppendUvarint(buf, encodingSegmentDAGCBOR)
 	case PayloadEncodingJWT:
-		res = binary.AppendUvarint(res, encodingSegmentJWT)
+		buf = binary.AppendUvarint(buf, encodingSegmentJWT)
+	case PayloadEncodingEIP712:
+		buf = binary.AppendUvarint(buf, encodingSegmentEIP712)
+	case PayloadEncodingWebAuthnRaw:
+		buf = binary.AppendUvarint(buf, encodingSegmentWebAuthn)
+		buf = binary.AppendUvarint(buf, encodingSegmentVerbatim)
+	case PayloadEncodingWebAuthnCbor:
+		buf = binary.AppendUvarint(buf, encodingSegmentWebAuthn)
+		buf = binary.AppendUvarint(buf, encodingSegmentDAGCBOR)
 	default:
-		panic(fmt.Sprintf("invalid encoding: %v", enc))
+		return buf, false
 	}
 
-	return res
+	return buf, true
+}
+
+// payloadEncodingLen returns the length of the serialized
+// PayloadEncoding, or zero if enc is not a valid PayloadEncoding.
+func payloadEncodingLen(enc PayloadEncoding) int {
+	var buf [2 * binary.MaxVarintLen64]byte
+	res, _ := appendPayloadEncoding(buf[:0], enc)
+
+	return len(res)
+}
+
+// validPayloadEncoding returns true if enc can be serialized.
+func validPayloadEncoding(enc PayloadEncoding) bool {
+	return payloadEncodingLen(enc) > 0
 }
 
 // Algorithm is (usually) the value representing the public key type of
@@ -184,3 +410,20 @@ func EncodePayloadEncoding(enc PayloadEncoding) []byte {
 // changed between varsig v0 and v1, so it's possible to have more than one
 // constant defined per implementation.
 type Algorithm uint64
+
+var algorithmNames = map[Algorithm]constantName{
+	AlgorithmRSA:   {"RSA", "AlgorithmRSA"},
+	AlgorithmEdDSA: {"EdDSA", "AlgorithmEdDSA"},
+	AlgorithmECDSA: {"ECDSA", "AlgorithmECDSA"},
+}
+
+// String returns the name of the signing algorithm.  Algorithms that
+// aren't implemented by this library are formatted as hexadecimal.
+func (a Algorithm) String() string {
+	return formatConstant(algorithmNames, a)
+}
+
+// GoString returns the Go syntax representation of the signing algorithm.
+func (a Algorithm) GoString() string {
+	return goStringConstant(algorithmNames, a, "Algorithm")
+}
@@ -2,6 +2,7 @@ package varsig_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"testing"
 
@@ -36,6 +37,11 @@ func TestDecodeHashAlgorithm(t *testing.T) {
 		hashAlg, err := varsig.DecodeHashAlgorithm(bytes.NewReader([]byte{0x42}))
 		require.ErrorIs(t, err, varsig.ErrUnknownHash)
 		require.Equal(t, varsig.HashUnspecified, hashAlg)
+
+		// On a plain reader, the offset is counted from the hash.
+		var decErr *varsig.DecodeError
+		require.ErrorAs(t, err, &decErr)
+		require.Equal(t, 0, decErr.Offset)
 	})
 }
 
@@ -97,3 +103,33 @@ func BenchmarkDecodePayloadEncoding(b *testing.B) {
 		_, _ = varsig.DecodePayloadEncoding(bytes.NewReader(data))
 	}
 }
+
+func TestConstantStrings(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		value    any
+		str      string
+		goString string
+	}{
+		{name: "Hash", value: varsig.HashSha2_512, str: "sha2-512", goString: "varsig.HashSha2_512"},
+		{name: "Hash - unknown", value: varsig.Hash(0xb261), str: "0xb261", goString: "varsig.Hash(0xb261)"},
+		{name: "PayloadEncoding", value: varsig.PayloadEncodingDAGCBOR, str: "dag-cbor", goString: "varsig.PayloadEncodingDAGCBOR"},
+		{name: "PayloadEncoding - unknown", value: varsig.PayloadEncoding(42), str: "PayloadEncoding(42)", goString: "varsig.PayloadEncoding(42)"},
+		{name: "Algorithm", value: varsig.AlgorithmEdDSA, str: "EdDSA", goString: "varsig.AlgorithmEdDSA"},
+		{name: "Algorithm - unknown", value: varsig.Algorithm(0x1000), str: "0x1000", goString: "varsig.Algorithm(0x1000)"},
+		{name: "EdDSACurve", value: varsig.CurveEd448, str: "Ed448", goString: "varsig.CurveEd448"},
+		{name: "ECDSACurve", value: varsig.CurveSecp256k1, str: "secp256k1", goString: "varsig.CurveSecp256k1"},
+		{name: "ECDSACurve - unknown", value: varsig.ECDSACurve(0x1204), str: "0x1204", goString: "varsig.ECDSACurve(0x1204)"},
+		{name: "Version", value: varsig.Version1, str: "v1", goString: "varsig.Version1"},
+		{name: "Version - unknown", value: varsig.Version(2), str: "v2", goString: "varsig.Version(2)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.str, fmt.Sprint(tc.value))
+			require.Equal(t, tc.goString, fmt.Sprintf("%#v", tc.value))
+		})
+	}
+}
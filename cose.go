@@ -0,0 +1,113 @@
+package varsig
+
+import "fmt"
+
+// COSEAlgorithm is the integer identifying a signing algorithm in the
+// [IANA COSE Algorithms registry].  These are the values found in the
+// "alg" header of COSE structures and in the "pubKeyCredParams" used by
+// WebAuthn.
+//
+// [IANA COSE Algorithms registry]: https://www.iana.org/assignments/cose/cose.xhtml#algorithms
+type COSEAlgorithm int64
+
+// Constant values for the COSE algorithms that can be described by the
+// Varsig types provided by this library.
+const (
+	COSEAlgorithmES256  = COSEAlgorithm(-7)
+	COSEAlgorithmES384  = COSEAlgorithm(-35)
+	COSEAlgorithmES512  = COSEAlgorithm(-36)
+	COSEAlgorithmES256K = COSEAlgorithm(-47)
+
+	// COSEAlgorithmEdDSA doesn't specify the curve.  It is mapped to
+	// Ed25519 as this is how it is used by WebAuthn authenticators.
+	COSEAlgorithmEdDSA   = COSEAlgorithm(-8)
+	COSEAlgorithmEd25519 = COSEAlgorithm(-19)
+	COSEAlgorithmEd448   = COSEAlgorithm(-53)
+
+	COSEAlgorithmRS256 = COSEAlgorithm(-257)
+	COSEAlgorithmRS384 = COSEAlgorithm(-258)
+	COSEAlgorithmRS512 = COSEAlgorithm(-259)
+)
+
+// ToCOSEAlgorithm returns the COSE algorithm describing the signature
+// produced according to the provided Varsig.  Only the signing algorithm,
+// curve and hash are considered, as COSE doesn't describe the payload
+// encoding or the RSA key length.
+//
+// Ed25519 is mapped to the (polymorphic) COSEAlgorithmEdDSA rather than
+// COSEAlgorithmEd25519 as the former is widely supported by WebAuthn
+// implementations.
+func ToCOSEAlgorithm(v Varsig) (COSEAlgorithm, error) {
+	switch vs := v.(type) {
+	case ECDSAVarsig:
+		switch {
+		case vs.curve == CurveP256 && vs.hashAlg == HashSha2_256:
+			return COSEAlgorithmES256, nil
+		case vs.curve == CurveP384 && vs.hashAlg == HashSha2_384:
+			return COSEAlgorithmES384, nil
+		case vs.curve == CurveP521 && vs.hashAlg == HashSha2_512:
+			return COSEAlgorithmES512, nil
+		case vs.curve == CurveSecp256k1 && vs.hashAlg == HashSha2_256:
+			return COSEAlgorithmES256K, nil
+		}
+	case EdDSAVarsig:
+		switch {
+		case vs.curve == CurveEd25519 && vs.hashAlg == HashSha2_512:
+			return COSEAlgorithmEdDSA, nil
+		case vs.curve == CurveEd448 && vs.hashAlg == HashShake_256:
+			return COSEAlgorithmEd448, nil
+		}
+	case RSAVarsig:
+		switch vs.hashAlg {
+		case HashSha2_256:
+			return COSEAlgorithmRS256, nil
+		case HashSha2_384:
+			return COSEAlgorithmRS384, nil
+		case HashSha2_512:
+			return COSEAlgorithmRS512, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: no COSE algorithm for %T", ErrUnsupportedCOSEAlgorithm, v)
+}
+
+// Varsig produces the varsig describing signatures created with the
+// COSE algorithm and the provided payload encoding.  Since COSE doesn't
+// describe the RSA key length, RSA algorithms must be converted using
+// RSAVarsig instead.
+func (a COSEAlgorithm) Varsig(payloadEncoding PayloadEncoding) (Varsig, error) {
+	switch a {
+	case COSEAlgorithmES256:
+		return ES256(payloadEncoding), nil
+	case COSEAlgorithmES384:
+		return ES384(payloadEncoding), nil
+	case COSEAlgorithmES512:
+		return ES512(payloadEncoding), nil
+	case COSEAlgorithmES256K:
+		return ES256K(payloadEncoding), nil
+	case COSEAlgorithmEdDSA, COSEAlgorithmEd25519:
+		return Ed25519(payloadEncoding), nil
+	case COSEAlgorithmEd448:
+		return Ed448(payloadEncoding), nil
+	case COSEAlgorithmRS256, COSEAlgorithmRS384, COSEAlgorithmRS512:
+		return nil, fmt.Errorf("%w: %d requires a key length", ErrUnsupportedCOSEAlgorithm, a)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedCOSEAlgorithm, a)
+	}
+}
+
+// RSAVarsig produces the varsig describing signatures created with the
+// COSE RSASSA-PKCS1-v1_5 algorithm, the provided key length (in bytes)
+// and payload encoding.
+func (a COSEAlgorithm) RSAVarsig(keyLength uint64, payloadEncoding PayloadEncoding) (RSAVarsig, error) {
+	switch a {
+	case COSEAlgorithmRS256:
+		return RS256(keyLength, payloadEncoding), nil
+	case COSEAlgorithmRS384:
+		return RS384(keyLength, payloadEncoding), nil
+	case COSEAlgorithmRS512:
+		return RS512(keyLength, payloadEncoding), nil
+	default:
+		return RSAVarsig{}, fmt.Errorf("%w: %d is not an RSA algorithm", ErrUnsupportedCOSEAlgorithm, a)
+	}
+}
@@ -0,0 +1,90 @@
+package varsig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestCOSEAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		varsig varsig.Varsig
+		cose   varsig.COSEAlgorithm
+	}{
+		{name: "ES256", varsig: varsig.ES256(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmES256},
+		{name: "ES384", varsig: varsig.ES384(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmES384},
+		{name: "ES512", varsig: varsig.ES512(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmES512},
+		{name: "ES256K", varsig: varsig.ES256K(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmES256K},
+		{name: "Ed25519", varsig: varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmEdDSA},
+		{name: "Ed448", varsig: varsig.Ed448(varsig.PayloadEncodingDAGCBOR), cose: varsig.COSEAlgorithmEd448},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cose, err := varsig.ToCOSEAlgorithm(tc.varsig)
+			require.NoError(t, err)
+			require.Equal(t, tc.cose, cose)
+
+			vs, err := cose.Varsig(varsig.PayloadEncodingDAGCBOR)
+			require.NoError(t, err)
+			require.Equal(t, tc.varsig, vs)
+		})
+	}
+
+	t.Run("RSA", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			varsig varsig.RSAVarsig
+			cose   varsig.COSEAlgorithm
+		}{
+			{varsig: varsig.RS256(0x100, varsig.PayloadEncodingVerbatim), cose: varsig.COSEAlgorithmRS256},
+			{varsig: varsig.RS384(0x100, varsig.PayloadEncodingVerbatim), cose: varsig.COSEAlgorithmRS384},
+			{varsig: varsig.RS512(0x100, varsig.PayloadEncodingVerbatim), cose: varsig.COSEAlgorithmRS512},
+		} {
+			cose, err := varsig.ToCOSEAlgorithm(tc.varsig)
+			require.NoError(t, err)
+			require.Equal(t, tc.cose, cose)
+
+			vs, err := cose.RSAVarsig(0x100, varsig.PayloadEncodingVerbatim)
+			require.NoError(t, err)
+			require.Equal(t, tc.varsig, vs)
+
+			_, err = cose.Varsig(varsig.PayloadEncodingVerbatim)
+			require.ErrorIs(t, err, varsig.ErrUnsupportedCOSEAlgorithm)
+		}
+	})
+
+	t.Run("Ed25519 fully specified", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := varsig.COSEAlgorithmEd25519.Varsig(varsig.PayloadEncodingVerbatim)
+		require.NoError(t, err)
+		require.Equal(t, varsig.Ed25519(varsig.PayloadEncodingVerbatim), vs)
+	})
+
+	t.Run("fails - no COSE equivalent", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.ToCOSEAlgorithm(varsig.NewECDSAVarsig(varsig.CurveP256, varsig.HashSha2_512, varsig.PayloadEncodingVerbatim))
+		require.ErrorIs(t, err, varsig.ErrUnsupportedCOSEAlgorithm)
+
+		_, err = varsig.ToCOSEAlgorithm(testVarsig{algo: testAlgorithm0})
+		require.ErrorIs(t, err, varsig.ErrUnsupportedCOSEAlgorithm)
+	})
+
+	t.Run("fails - unknown COSE algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.COSEAlgorithm(-37).Varsig(varsig.PayloadEncodingVerbatim) // PS256
+		require.ErrorIs(t, err, varsig.ErrUnsupportedCOSEAlgorithm)
+
+		_, err = varsig.COSEAlgorithmES256.RSAVarsig(0x100, varsig.PayloadEncodingVerbatim)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedCOSEAlgorithm)
+	})
+}
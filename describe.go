@@ -0,0 +1,150 @@
+package varsig
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// Names of the parameters returned by Describe, which are also the field
+// names of DecodeErrors.  ParameterPrefix is only used by DecodeErrors,
+// since every varsig has the same prefix.
+const (
+	ParameterPrefix               = "prefix"
+	ParameterVersion              = "version"
+	ParameterAlgorithm            = "algorithm"
+	ParameterCurve                = "curve"
+	ParameterHash                 = "hash"
+	ParameterKeyLength            = "key-length"
+	ParameterPayloadEncoding      = "payload-encoding"
+	ParameterPayloadEncodingInner = "payload-encoding-inner"
+)
+
+// Parameter is a named field of a varsig along with its raw (multicodec)
+// value and a human-readable representation of that value.  A parameter
+// that can't be encoded (such as an invalid payload encoding) is flagged
+// as Invalid: its Value is zero and its Text describes the Go value.
+type Parameter struct {
+	Name    string `json:"name"`
+	Value   uint64 `json:"value"`
+	Text    string `json:"text"`
+	Invalid bool   `json:"invalid,omitempty"`
+}
+
+// Describer is implemented by Varsig types that can list all their
+// parameters.  Third-party Varsig types should implement Describer if
+// they contain parameters that aren't available from the Varsig
+// interface.
+type Describer interface {
+	// Describe returns the varsig's parameters in the order they are
+	// encoded.
+	Describe() []Parameter
+}
+
+var (
+	_ Describer = EdDSAVarsig{}
+	_ Describer = ECDSAVarsig{}
+	_ Describer = RSAVarsig{}
+)
+
+var encodingSegmentNames = map[uint64]string{
+	encodingSegmentVerbatim: "verbatim",
+	encodingSegmentDAGPB:    "dag-pb",
+	encodingSegmentDAGCBOR:  "dag-cbor",
+	encodingSegmentDAGJSON:  "dag-json",
+	encodingSegmentEIP191:   "eip191",
+	encodingSegmentJWT:      "jwt",
+	encodingSegmentEIP712:   "eip712",
+	encodingSegmentWebAuthn: "webauthn",
+}
+
+// Describe returns the parameters of any Varsig in the order they are
+// encoded.  Varsig types implementing Describer describe themselves,
+// otherwise the parameters available from the Varsig interface are
+// listed.
+func Describe(v Varsig) []Parameter {
+	if d, ok := v.(Describer); ok {
+		return d.Describe()
+	}
+
+	params := describeHeader(v.Version(), v.Algorithm())
+
+	if h := v.Hash(); h != HashUnspecified {
+		params = append(params, describeHash(h))
+	}
+
+	return append(params, describePayloadEncoding(v.PayloadEncoding())...)
+}
+
+// Describe returns the EdDSAVarsig's parameters in the order they are
+// encoded.
+func (v EdDSAVarsig) Describe() []Parameter {
+	params := describeHeader(v.Version(), v.algo)
+	params = append(params,
+		Parameter{Name: ParameterCurve, Value: uint64(v.curve), Text: v.curve.String()},
+		describeHash(v.hashAlg),
+	)
+
+	return append(params, describePayloadEncoding(v.payEnc)...)
+}
+
+// Describe returns the ECDSAVarsig's parameters in the order they are
+// encoded.
+func (v ECDSAVarsig) Describe() []Parameter {
+	params := describeHeader(v.Version(), v.algo)
+	params = append(params,
+		Parameter{Name: ParameterCurve, Value: uint64(v.curve), Text: v.curve.String()},
+		describeHash(v.hashAlg),
+	)
+
+	return append(params, describePayloadEncoding(v.payEnc)...)
+}
+
+// Describe returns the RSAVarsig's parameters in the order they are
+// encoded.
+func (v RSAVarsig) Describe() []Parameter {
+	params := describeHeader(v.Version(), v.algo)
+	params = append(params,
+		describeHash(v.hashAlg),
+		Parameter{Name: ParameterKeyLength, Value: v.keyLen, Text: strconv.FormatUint(v.keyLen, 10)},
+	)
+
+	return append(params, describePayloadEncoding(v.payEnc)...)
+}
+
+func describeHeader(vers Version, algo Algorithm) []Parameter {
+	// Room for the parameters of the built-in Varsig types.
+	params := make([]Parameter, 0, 6)
+
+	return append(params,
+		Parameter{Name: ParameterVersion, Value: uint64(vers), Text: vers.String()},
+		Parameter{Name: ParameterAlgorithm, Value: uint64(algo), Text: algo.String()},
+	)
+}
+
+func describeHash(h Hash) Parameter {
+	return Parameter{Name: ParameterHash, Value: uint64(h), Text: h.String()}
+}
+
+// describePayloadEncoding returns a parameter for each of the segments
+// of the encoded payload encoding.  Invalid payload encodings are
+// described by a single Invalid parameter.
+func describePayloadEncoding(enc PayloadEncoding) []Parameter {
+	var buf [16]byte
+
+	data, ok := appendPayloadEncoding(buf[:0], enc)
+	if !ok {
+		return []Parameter{{Name: ParameterPayloadEncoding, Text: enc.String(), Invalid: true}}
+	}
+
+	params := make([]Parameter, 0, 2)
+	names := []string{ParameterPayloadEncoding, ParameterPayloadEncodingInner}
+
+	for i := 0; len(data) > 0 && i < len(names); i++ {
+		seg, n := binary.Uvarint(data)
+		data = data[n:]
+
+		params = append(params, Parameter{Name: names[i], Value: seg, Text: encodingSegmentNames[seg]})
+	}
+
+	return params
+}
@@ -0,0 +1,95 @@
+package varsig_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		varsig varsig.Varsig
+		params []varsig.Parameter
+	}{
+		{
+			name:   "EdDSA",
+			varsig: varsig.Ed25519(varsig.PayloadEncodingDAGCBOR),
+			params: []varsig.Parameter{
+				{Name: varsig.ParameterVersion, Value: 1, Text: "v1"},
+				{Name: varsig.ParameterAlgorithm, Value: 0xed, Text: "EdDSA"},
+				{Name: varsig.ParameterCurve, Value: 0xed, Text: "Ed25519"},
+				{Name: varsig.ParameterHash, Value: 0x13, Text: "sha2-512"},
+				{Name: varsig.ParameterPayloadEncoding, Value: 0x71, Text: "dag-cbor"},
+			},
+		},
+		{
+			name:   "ECDSA with nested payload encoding",
+			varsig: must(varsig.EIP191(varsig.PayloadEncodingEIP191Raw)),
+			params: []varsig.Parameter{
+				{Name: varsig.ParameterVersion, Value: 1, Text: "v1"},
+				{Name: varsig.ParameterAlgorithm, Value: 0xec, Text: "ECDSA"},
+				{Name: varsig.ParameterCurve, Value: 0xe7, Text: "secp256k1"},
+				{Name: varsig.ParameterHash, Value: 0x1b, Text: "keccak-256"},
+				{Name: varsig.ParameterPayloadEncoding, Value: 0xe191, Text: "eip191"},
+				{Name: varsig.ParameterPayloadEncodingInner, Value: 0x5f, Text: "verbatim"},
+			},
+		},
+		{
+			name:   "RSA",
+			varsig: varsig.RS256(0x100, varsig.PayloadEncodingDAGJSON),
+			params: []varsig.Parameter{
+				{Name: varsig.ParameterVersion, Value: 1, Text: "v1"},
+				{Name: varsig.ParameterAlgorithm, Value: 0x1205, Text: "RSA"},
+				{Name: varsig.ParameterHash, Value: 0x12, Text: "sha2-256"},
+				{Name: varsig.ParameterKeyLength, Value: 0x100, Text: "256"},
+				{Name: varsig.ParameterPayloadEncoding, Value: 0x0129, Text: "dag-json"},
+			},
+		},
+		{
+			name:   "invalid payload encoding",
+			varsig: varsig.ES256(varsig.PayloadEncodingUnspecified),
+			params: []varsig.Parameter{
+				{Name: varsig.ParameterVersion, Value: 1, Text: "v1"},
+				{Name: varsig.ParameterAlgorithm, Value: 0xec, Text: "ECDSA"},
+				{Name: varsig.ParameterCurve, Value: 0x1200, Text: "P-256"},
+				{Name: varsig.ParameterHash, Value: 0x12, Text: "sha2-256"},
+				{Name: varsig.ParameterPayloadEncoding, Value: 0, Text: "unspecified", Invalid: true},
+			},
+		},
+		{
+			name:   "third-party",
+			varsig: testVarsig{algo: testAlgorithm0, payEnc: varsig.PayloadEncodingVerbatim},
+			params: []varsig.Parameter{
+				{Name: varsig.ParameterVersion, Value: 1, Text: "v1"},
+				{Name: varsig.ParameterAlgorithm, Value: 0x1000, Text: "0x1000"},
+				{Name: varsig.ParameterPayloadEncoding, Value: 0x5f, Text: "verbatim"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.params, varsig.Describe(tc.varsig))
+		})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(varsig.Describe(varsig.ES256(varsig.PayloadEncodingVerbatim)))
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"name": "version", "value": 1, "text": "v1"},
+			{"name": "algorithm", "value": 236, "text": "ECDSA"},
+			{"name": "curve", "value": 4608, "text": "P-256"},
+			{"name": "hash", "value": 18, "text": "sha2-256"},
+			{"name": "payload-encoding", "value": 95, "text": "verbatim"}
+		]`, string(data))
+	})
+}
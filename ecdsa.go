@@ -21,12 +21,41 @@ const (
 	CurveP521      = ECDSACurve(0x1202)
 )
 
-func decodeECDSACurve(r BytesReader) (ECDSACurve, error) {
-	u, err := binary.ReadUvarint(r)
+var ecdsaCurveNames = map[ECDSACurve]constantName{
+	CurveSecp256k1: {"secp256k1", "CurveSecp256k1"},
+	CurveP256:      {"P-256", "CurveP256"},
+	CurveP384:      {"P-384", "CurveP384"},
+	CurveP521:      {"P-521", "CurveP521"},
+}
+
+// String returns the name of the elliptic curve.
+func (c ECDSACurve) String() string {
+	return formatConstant(ecdsaCurveNames, c)
+}
+
+// GoString returns the Go syntax representation of the elliptic curve.
+func (c ECDSACurve) GoString() string {
+	return goStringConstant(ecdsaCurveNames, c, "ECDSACurve")
+}
+
+func decodeECDSACurve(d *decodeReader) (ECDSACurve, error) {
+	off := d.offset
+
+	u, err := d.uvarint(ParameterCurve)
 	if err != nil {
 		return 0, err
 	}
 
+	curve, err := ecdsaCurveFor(u)
+	if err != nil {
+		return 0, decodeError(ParameterCurve, off, u, err)
+	}
+
+	return curve, nil
+}
+
+// ecdsaCurveFor validates the decoded curve.
+func ecdsaCurveFor(u uint64) (ECDSACurve, error) {
 	switch curve := ECDSACurve(u); curve {
 	case CurveSecp256k1, CurveP256, CurveP384, CurveP521:
 		return curve, nil
@@ -35,7 +64,10 @@ func decodeECDSACurve(r BytesReader) (ECDSACurve, error) {
 	}
 }
 
-var _ Varsig = ECDSAVarsig{}
+var (
+	_ Varsig   = ECDSAVarsig{}
+	_ Appender = ECDSAVarsig{}
+)
 
 // ECDSAVarsig is a varsig that encodes the parameters required to describe
 // an ECDSA signature.
@@ -70,32 +102,93 @@ func (v ECDSAVarsig) Hash() Hash {
 	return v.hashAlg
 }
 
+// String returns a human-readable description of the ECDSAVarsig, formatted
+// as "algorithm/curve/hash/payload-encoding version".
+func (v ECDSAVarsig) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s %s", v.algo, v.curve, v.hashAlg, v.payEnc, v.Version())
+}
+
+// GoString returns the Go syntax representation of the ECDSAVarsig.
+func (v ECDSAVarsig) GoString() string {
+	return fmt.Sprintf("varsig.NewECDSAVarsig(%#v, %#v, %#v)", v.curve, v.hashAlg, v.payEnc)
+}
+
 // Encode returns the encoded byte format of the ECDSAVarsig.
 func (v ECDSAVarsig) Encode() []byte {
-	buf := v.encode()
+	return v.AppendEncode(make([]byte, 0, v.EncodedLen()))
+}
 
-	buf = binary.AppendUvarint(buf, uint64(v.curve))
-	buf = binary.AppendUvarint(buf, uint64(v.hashAlg))
-	buf = append(buf, EncodePayloadEncoding(v.payEnc)...)
+// AppendEncode appends the encoded byte format of the ECDSAVarsig to dst and
+// returns the extended buffer.  No allocation occurs when dst has enough
+// capacity.
+func (v ECDSAVarsig) AppendEncode(dst []byte) []byte {
+	dst = v.appendHeader(dst)
+	dst = binary.AppendUvarint(dst, uint64(v.curve))
+	dst = binary.AppendUvarint(dst, uint64(v.hashAlg))
 
-	return buf
+	return mustAppendPayloadEncoding(dst, v.payEnc)
+}
+
+// EncodedLen returns the length of the encoded byte format of the
+// ECDSAVarsig.
+func (v ECDSAVarsig) EncodedLen() int {
+	return v.headerLen() + uvarintLen(uint64(v.curve)) + uvarintLen(uint64(v.hashAlg)) + payloadEncodingLen(v.payEnc)
 }
 
 func decodeECDSA(r BytesReader) (Varsig, error) {
-	curve, err := decodeECDSACurve(r)
-	if err != nil {
+	var v ECDSAVarsig
+	if err := v.decodeFields(readerOf(r)); err != nil {
 		return nil, err
 	}
 
-	hashAlg, err := DecodeHashAlgorithm(r)
+	return v, nil
+}
+
+// DecodeBytes decodes the ECDSA varsig at the start of data into v and
+// returns the number of bytes consumed, with the behavior changed by the
+// provided options.  The data is parsed in place and, unless an error is
+// returned or options are provided, without allocating.
+func (v *ECDSAVarsig) DecodeBytes(data []byte, opts ...DecodeOption) (int, error) {
+	d := newSliceReader(data, opts)
+
+	if err := d.expectAlgorithm(AlgorithmECDSA); err != nil {
+		return 0, err
+	}
+
+	var dec ECDSAVarsig
+	if err := dec.decodeFields(&d); err != nil {
+		return 0, err
+	}
+
+	if d.checksDecoded() {
+		if err := d.checkDecoded(dec); err != nil {
+			return 0, err
+		}
+	}
+
+	*v = dec
+
+	return d.offset, nil
+}
+
+// decodeFields decodes the fields following the algorithm into v.
+func (v *ECDSAVarsig) decodeFields(d *decodeReader) error {
+	curve, err := decodeECDSACurve(d)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	payEnc, err := DecodePayloadEncoding(r)
+	hashAlg, err := d.hash()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return NewECDSAVarsig(curve, hashAlg, payEnc), nil
+	payEnc, err := d.payloadEncoding()
+	if err != nil {
+		return err
+	}
+
+	*v = NewECDSAVarsig(curve, hashAlg, payEnc)
+
+	return nil
 }
@@ -19,12 +19,39 @@ const (
 	CurveEd448   = EdDSACurve(0x1203)
 )
 
-func decodeEdDSACurve(r BytesReader) (EdDSACurve, error) {
-	u, err := binary.ReadUvarint(r)
+var edDSACurveNames = map[EdDSACurve]constantName{
+	CurveEd25519: {"Ed25519", "CurveEd25519"},
+	CurveEd448:   {"Ed448", "CurveEd448"},
+}
+
+// String returns the name of the Edwards curve.
+func (c EdDSACurve) String() string {
+	return formatConstant(edDSACurveNames, c)
+}
+
+// GoString returns the Go syntax representation of the Edwards curve.
+func (c EdDSACurve) GoString() string {
+	return goStringConstant(edDSACurveNames, c, "EdDSACurve")
+}
+
+func decodeEdDSACurve(d *decodeReader) (EdDSACurve, error) {
+	off := d.offset
+
+	u, err := d.uvarint(ParameterCurve)
 	if err != nil {
 		return 0, err
 	}
 
+	curve, err := edDSACurveFor(u)
+	if err != nil {
+		return 0, decodeError(ParameterCurve, off, u, err)
+	}
+
+	return curve, nil
+}
+
+// edDSACurveFor validates the decoded curve.
+func edDSACurveFor(u uint64) (EdDSACurve, error) {
 	switch curve := EdDSACurve(u); curve {
 	case CurveEd25519, CurveEd448:
 		return curve, nil
@@ -33,7 +60,10 @@ func decodeEdDSACurve(r BytesReader) (EdDSACurve, error) {
 	}
 }
 
-var _ Varsig = EdDSAVarsig{}
+var (
+	_ Varsig   = EdDSAVarsig{}
+	_ Appender = EdDSAVarsig{}
+)
 
 // EdDSAVarsig is a varsig that encodes the parameters required to describe
 // an EdDSA signature.
@@ -68,32 +98,93 @@ func (v EdDSAVarsig) Hash() Hash {
 	return v.hashAlg
 }
 
+// String returns a human-readable description of the EdDSAVarsig, formatted
+// as "algorithm/curve/hash/payload-encoding version".
+func (v EdDSAVarsig) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s %s", v.algo, v.curve, v.hashAlg, v.payEnc, v.Version())
+}
+
+// GoString returns the Go syntax representation of the EdDSAVarsig.
+func (v EdDSAVarsig) GoString() string {
+	return fmt.Sprintf("varsig.NewEdDSAVarsig(%#v, %#v, %#v)", v.curve, v.hashAlg, v.payEnc)
+}
+
 // Encode returns the encoded byte format of the EdDSAVarsig.
 func (v EdDSAVarsig) Encode() []byte {
-	buf := v.encode()
+	return v.AppendEncode(make([]byte, 0, v.EncodedLen()))
+}
 
-	buf = binary.AppendUvarint(buf, uint64(v.curve))
-	buf = binary.AppendUvarint(buf, uint64(v.hashAlg))
-	buf = append(buf, EncodePayloadEncoding(v.payEnc)...)
+// AppendEncode appends the encoded byte format of the EdDSAVarsig to dst and
+// returns the extended buffer.  No allocation occurs when dst has enough
+// capacity.
+func (v EdDSAVarsig) AppendEncode(dst []byte) []byte {
+	dst = v.appendHeader(dst)
+	dst = binary.AppendUvarint(dst, uint64(v.curve))
+	dst = binary.AppendUvarint(dst, uint64(v.hashAlg))
 
-	return buf
+	return mustAppendPayloadEncoding(dst, v.payEnc)
+}
+
+// EncodedLen returns the length of the encoded byte format of the
+// EdDSAVarsig.
+func (v EdDSAVarsig) EncodedLen() int {
+	return v.headerLen() + uvarintLen(uint64(v.curve)) + uvarintLen(uint64(v.hashAlg)) + payloadEncodingLen(v.payEnc)
 }
 
 func decodeEdDSA(r BytesReader) (Varsig, error) {
-	curve, err := decodeEdDSACurve(r)
-	if err != nil {
+	var v EdDSAVarsig
+	if err := v.decodeFields(readerOf(r)); err != nil {
 		return nil, err
 	}
 
-	hashAlg, err := DecodeHashAlgorithm(r)
+	return v, nil
+}
+
+// DecodeBytes decodes the EdDSA varsig at the start of data into v and
+// returns the number of bytes consumed, with the behavior changed by the
+// provided options.  The data is parsed in place and, unless an error is
+// returned or options are provided, without allocating.
+func (v *EdDSAVarsig) DecodeBytes(data []byte, opts ...DecodeOption) (int, error) {
+	d := newSliceReader(data, opts)
+
+	if err := d.expectAlgorithm(AlgorithmEdDSA); err != nil {
+		return 0, err
+	}
+
+	var dec EdDSAVarsig
+	if err := dec.decodeFields(&d); err != nil {
+		return 0, err
+	}
+
+	if d.checksDecoded() {
+		if err := d.checkDecoded(dec); err != nil {
+			return 0, err
+		}
+	}
+
+	*v = dec
+
+	return d.offset, nil
+}
+
+// decodeFields decodes the fields following the algorithm into v.
+func (v *EdDSAVarsig) decodeFields(d *decodeReader) error {
+	curve, err := decodeEdDSACurve(d)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	payEnc, err := DecodePayloadEncoding(r)
+	hashAlg, err := d.hash()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return NewEdDSAVarsig(curve, hashAlg, payEnc), nil
+	payEnc, err := d.payloadEncoding()
+	if err != nil {
+		return err
+	}
+
+	*v = NewEdDSAVarsig(curve, hashAlg, payEnc)
+
+	return nil
 }
@@ -0,0 +1,458 @@
+package varsig
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Varsigs using PayloadEncodingEIP712 describe signatures produced by
+// wallets implementing eth_signTypedData_v4, where the signed payload is
+// presented to the signer as typed structured data.  The signature is
+// generated over:
+//
+//	keccak256(0x19 || 0x01 || domainSeparator || hashStruct(message))
+//
+// The EIP712TypedData type computes this value from a schema (the types),
+// the domain and the message.
+//
+// See https://eips.ethereum.org/EIPS/eip-712
+
+// eip712DomainType is the name of the struct type describing the
+// signing domain.
+const eip712DomainType = "EIP712Domain"
+
+// EIP712Field describes a member of an EIP-712 struct type.
+type EIP712Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// EIP712Types maps the name of each EIP-712 struct type to its members.
+// The "EIP712Domain" type must be included to describe the domain.
+type EIP712Types map[string][]EIP712Field
+
+// EIP712TypedData contains the schema and values that are hashed and
+// signed by eth_signTypedData_v4.  The JSON representation of this type
+// is the one used by the eth_signTypedData_v4 RPC method.
+//
+// Values may be provided as the types produced by encoding/json or as
+// their Go equivalents: integers may be any Go integer type, *big.Int,
+// json.Number or a decimal or 0x-prefixed hexadecimal string; bytes and
+// addresses may be []byte or a 0x-prefixed hexadecimal string.
+type EIP712TypedData struct {
+	Types       EIP712Types    `json:"types"`
+	PrimaryType string         `json:"primaryType"`
+	Domain      map[string]any `json:"domain"`
+	Message     map[string]any `json:"message"`
+}
+
+// ParseEIP712TypedData parses the JSON representation of typed data, as
+// used by the eth_signTypedData_v4 RPC method.  Unlike json.Unmarshal,
+// numbers are kept as json.Number so that uint256 values aren't
+// truncated.
+func ParseEIP712TypedData(data []byte) (EIP712TypedData, error) {
+	var td EIP712TypedData
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&td); err != nil {
+		return EIP712TypedData{}, fmt.Errorf("%w: %w", ErrInvalidEIP712TypedData, err)
+	}
+
+	return td, nil
+}
+
+// SigningHash returns the hash of the typed data that is signed by the
+// wallet.
+func (td EIP712TypedData) SigningHash() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, message), nil
+}
+
+// DomainSeparator returns the hashStruct of the typed data's domain.
+func (td EIP712TypedData) DomainSeparator() ([]byte, error) {
+	return td.HashStruct(eip712DomainType, td.Domain)
+}
+
+// HashStruct returns the EIP-712 hashStruct of the data, interpreted as
+// the named struct type.
+func (td EIP712TypedData) HashStruct(typeName string, data map[string]any) ([]byte, error) {
+	enc, err := td.encodeData(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256(enc), nil
+}
+
+// TypeHash returns the hash of the encoded type.
+func (td EIP712TypedData) TypeHash(typeName string) ([]byte, error) {
+	encType, err := td.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256([]byte(encType)), nil
+}
+
+// EncodeType returns the EIP-712 encoding of the named struct type, which
+// is followed by the encodings of all the struct types it references,
+// sorted by name.
+func (td EIP712TypedData) EncodeType(typeName string) (string, error) {
+	if _, ok := td.Types[typeName]; !ok {
+		return "", fmt.Errorf("%w: unknown type %q", ErrInvalidEIP712TypedData, typeName)
+	}
+
+	deps := map[string]struct{}{}
+	td.collectDependencies(typeName, deps)
+	delete(deps, typeName)
+
+	names := make([]string, 0, len(deps)+1)
+	for name := range deps {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	names = slices.Insert(names, 0, typeName)
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte('(')
+
+		for i, field := range td.Types[name] {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+
+			sb.WriteString(field.Type)
+			sb.WriteByte(' ')
+			sb.WriteString(field.Name)
+		}
+
+		sb.WriteByte(')')
+	}
+
+	return sb.String(), nil
+}
+
+func (td EIP712TypedData) collectDependencies(typeName string, deps map[string]struct{}) {
+	if _, ok := deps[typeName]; ok {
+		return
+	}
+
+	if _, ok := td.Types[typeName]; !ok {
+		return
+	}
+
+	deps[typeName] = struct{}{}
+
+	for _, field := range td.Types[typeName] {
+		td.collectDependencies(eip712BaseType(field.Type), deps)
+	}
+}
+
+func (td EIP712TypedData) encodeData(typeName string, data map[string]any) ([]byte, error) {
+	typeHash, err := td.TypeHash(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := td.Types[typeName]
+	enc := make([]byte, 0, 32*(len(fields)+1))
+	enc = append(enc, typeHash...)
+
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing value for %s.%s", ErrInvalidEIP712TypedData, typeName, field.Name)
+		}
+
+		word, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Name, err)
+		}
+
+		enc = append(enc, word...)
+	}
+
+	return enc, nil
+}
+
+// encodeValue returns the 32 byte encoding of a value of the named type.
+func (td EIP712TypedData) encodeValue(typ string, value any) ([]byte, error) {
+	if elemType, ok := strings.CutSuffix(typ, "]"); ok {
+		return td.encodeArray(elemType, value)
+	}
+
+	if _, ok := td.Types[typ]; ok {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected an object for %s, got %T", ErrInvalidEIP712TypedData, typ, value)
+		}
+
+		return td.HashStruct(typ, m)
+	}
+
+	return encodeEIP712Atomic(typ, value)
+}
+
+// encodeArray encodes an array value where typ is the array's type
+// without its closing bracket (e.g. "uint256[" or "Person[3".)
+func (td EIP712TypedData) encodeArray(typ string, value any) ([]byte, error) {
+	i := strings.LastIndexByte(typ, '[')
+	if i < 0 {
+		return nil, fmt.Errorf("%w: malformed array type %q", ErrInvalidEIP712TypedData, typ+"]")
+	}
+
+	elemType, length := typ[:i], typ[i+1:]
+
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an array for %s], got %T", ErrInvalidEIP712TypedData, typ, value)
+	}
+
+	if length != "" {
+		n, err := strconv.Atoi(length)
+		if err != nil || n != len(items) {
+			return nil, fmt.Errorf("%w: expected %s items for %s], got %d", ErrInvalidEIP712TypedData, length, typ, len(items))
+		}
+	}
+
+	enc := make([]byte, 0, 32*len(items))
+
+	for _, item := range items {
+		word, err := td.encodeValue(elemType, item)
+		if err != nil {
+			return nil, err
+		}
+
+		enc = append(enc, word...)
+	}
+
+	return keccak256(enc), nil
+}
+
+// eip712BaseType strips any array suffixes from the type name.
+func eip712BaseType(typ string) string {
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		return typ[:i]
+	}
+
+	return typ
+}
+
+func encodeEIP712Atomic(typ string, value any) ([]byte, error) {
+	switch {
+	case typ == "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a string, got %T", ErrInvalidEIP712TypedData, value)
+		}
+
+		return keccak256([]byte(s)), nil
+	case typ == "bytes":
+		b, err := eip712Bytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return keccak256(b), nil
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a bool, got %T", ErrInvalidEIP712TypedData, value)
+		}
+
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+
+		return word, nil
+	case typ == "address":
+		b, err := eip712Bytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) != 20 {
+			return nil, fmt.Errorf("%w: address must be 20 bytes, got %d", ErrInvalidEIP712TypedData, len(b))
+		}
+
+		return append(make([]byte, 12, 32), b...), nil
+	case strings.HasPrefix(typ, "bytes"):
+		return encodeEIP712FixedBytes(typ, value)
+	case strings.HasPrefix(typ, "uint"):
+		return encodeEIP712Integer(typ, "uint", false, value)
+	case strings.HasPrefix(typ, "int"):
+		return encodeEIP712Integer(typ, "int", true, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidEIP712TypedData, typ)
+	}
+}
+
+func encodeEIP712FixedBytes(typ string, value any) ([]byte, error) {
+	size, err := strconv.Atoi(typ[len("bytes"):])
+	if err != nil || size < 1 || size > 32 {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidEIP712TypedData, typ)
+	}
+
+	b, err := eip712Bytes(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != size {
+		return nil, fmt.Errorf("%w: %s must be %d bytes, got %d", ErrInvalidEIP712TypedData, typ, size, len(b))
+	}
+
+	word := make([]byte, 32)
+	copy(word, b)
+
+	return word, nil
+}
+
+func encodeEIP712Integer(typ, prefix string, signed bool, value any) ([]byte, error) {
+	size := 256
+	if s := typ[len(prefix):]; s != "" {
+		var err error
+
+		size, err = strconv.Atoi(s)
+		if err != nil || size < 8 || size > 256 || size%8 != 0 {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidEIP712TypedData, typ)
+		}
+	}
+
+	n, err := eip712Integer(value)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size))
+	lower := new(big.Int)
+
+	if signed {
+		limit.Rsh(limit, 1)
+		lower.Neg(limit)
+	}
+
+	if n.Cmp(lower) < 0 || n.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("%w: %s out of range for %s", ErrInvalidEIP712TypedData, n, typ)
+	}
+
+	if n.Sign() < 0 {
+		// Two's complement over 256 bits.
+		n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	return n.FillBytes(make([]byte, 32)), nil
+}
+
+func eip712Integer(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("%w: %v is not an exact integer", ErrInvalidEIP712TypedData, v)
+		}
+
+		return big.NewInt(int64(v)), nil
+	case json.Number:
+		return eip712Integer(string(v))
+	case string:
+		// Only 0x-prefixed strings are hexadecimal: unlike with base 0,
+		// "010" is 10 (not octal 8) and underscores are rejected.
+		text, base := v, 10
+		if h, ok := strings.CutPrefix(v, "0x"); ok {
+			text, base = h, 16
+		}
+
+		if strings.HasPrefix(text, "+") || (base == 16 && strings.HasPrefix(text, "-")) {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidEIP712TypedData, v)
+		}
+
+		n, ok := new(big.Int).SetString(text, base)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidEIP712TypedData, v)
+		}
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: expected an integer, got %T", ErrInvalidEIP712TypedData, value)
+	}
+}
+
+func eip712Bytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		s, ok := strings.CutPrefix(v, "0x")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is missing the 0x prefix", ErrInvalidEIP712TypedData, v)
+		}
+
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEIP712TypedData, err)
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%w: expected bytes, got %T", ErrInvalidEIP712TypedData, value)
+	}
+}
+
+// keccak256 returns the legacy Keccak-256 hash used by Ethereum, which
+// differs from the standardized SHA3-256 by its padding, of the
+// concatenated data.
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		h.Write(b)
+	}
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,179 @@
+package varsig_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// This is the example provided with the EIP-712 specification.
+const eip712ExampleJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestEIP712TypedData(t *testing.T) {
+	t.Parallel()
+
+	td, err := varsig.ParseEIP712TypedData([]byte(eip712ExampleJSON))
+	require.NoError(t, err)
+
+	t.Run("EncodeType", func(t *testing.T) {
+		t.Parallel()
+
+		encType, err := td.EncodeType("Mail")
+		require.NoError(t, err)
+		require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", encType)
+
+		typeHash, err := td.TypeHash("Mail")
+		require.NoError(t, err)
+		require.Equal(t, "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2", hex.EncodeToString(typeHash))
+	})
+
+	t.Run("DomainSeparator", func(t *testing.T) {
+		t.Parallel()
+
+		ds, err := td.DomainSeparator()
+		require.NoError(t, err)
+		require.Equal(t, "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hex.EncodeToString(ds))
+	})
+
+	t.Run("HashStruct", func(t *testing.T) {
+		t.Parallel()
+
+		hs, err := td.HashStruct(td.PrimaryType, td.Message)
+		require.NoError(t, err)
+		require.Equal(t, "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e", hex.EncodeToString(hs))
+	})
+
+	t.Run("SigningHash", func(t *testing.T) {
+		t.Parallel()
+
+		sh, err := td.SigningHash()
+		require.NoError(t, err)
+		require.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(sh))
+	})
+
+	t.Run("Go values", func(t *testing.T) {
+		t.Parallel()
+
+		goTD := td
+		goTD.Domain = map[string]any{
+			"name":              "Ether Mail",
+			"version":           "1",
+			"chainId":           big.NewInt(1),
+			"verifyingContract": must(hex.DecodeString("CcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")),
+		}
+
+		ds, err := goTD.DomainSeparator()
+		require.NoError(t, err)
+		require.Equal(t, "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hex.EncodeToString(ds))
+	})
+}
+
+func TestEIP712TypedData_values(t *testing.T) {
+	t.Parallel()
+
+	types := varsig.EIP712Types{
+		"EIP712Domain": {},
+		"Values": {
+			{Name: "flag", Type: "bool"},
+			{Name: "small", Type: "int8"},
+			{Name: "id", Type: "bytes4"},
+			{Name: "data", Type: "bytes"},
+			{Name: "list", Type: "uint16[2]"},
+		},
+	}
+
+	valid := map[string]any{
+		"flag":  true,
+		"small": -128,
+		"id":    "0x01020304",
+		"data":  []byte{},
+		"list":  []any{10, "0xffff"},
+	}
+
+	want, err := varsig.EIP712TypedData{Types: types}.HashStruct("Values", valid)
+	require.NoError(t, err)
+
+	// Strings without the 0x prefix are decimal, even with a leading zero.
+	for _, list := range [][]any{{"010", "65535"}, {json.Number("10"), "0xFFFF"}} {
+		data := map[string]any{}
+		for k, v := range valid {
+			data[k] = v
+		}
+
+		data["list"] = list
+
+		got, err := varsig.EIP712TypedData{Types: types}.HashStruct("Values", data)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		field string
+		value any
+	}{
+		{name: "int out of range", field: "small", value: 128},
+		{name: "fixed bytes wrong length", field: "id", value: "0x0102"},
+		{name: "bytes without prefix", field: "data", value: "0102"},
+		{name: "array wrong length", field: "list", value: []any{1}},
+		{name: "uint out of range", field: "list", value: []any{1, 0x10000}},
+		{name: "underscore", field: "list", value: []any{1, "1_0"}},
+		{name: "signed hexadecimal", field: "small", value: "0x-1"},
+		{name: "explicit plus sign", field: "small", value: "+1"},
+		{name: "not a bool", field: "flag", value: "true"},
+		{name: "missing value", field: "flag", value: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := map[string]any{}
+			for k, v := range valid {
+				data[k] = v
+			}
+
+			if tc.value == nil {
+				delete(data, tc.field)
+			} else {
+				data[tc.field] = tc.value
+			}
+
+			_, err := varsig.EIP712TypedData{Types: types}.HashStruct("Values", data)
+			require.ErrorIs(t, err, varsig.ErrInvalidEIP712TypedData)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package varsig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// invalidKeyPrefix starts the keys of varsigs that can't be encoded.  It
+// can't be confused with an encoded varsig as those start with Prefix.
+const invalidKeyPrefix = "!"
+
+// Key returns a stable string identifying the varsig, which is suitable
+// for use as a map key.  Two varsigs have the same key if, and only if,
+// they are Equal.
+//
+// The key of a varsig is its encoded bytes.  Varsigs that can't be encoded
+// (for instance because their payload encoding is invalid) are instead
+// identified by their parameters, as returned by their Describe method, or
+// by their concrete type and the values of all their fields if they don't
+// implement Describer.  An Any is identified by the Varsig it holds.  Key
+// never panics, even if the varsig's Encode method does.
+func Key(v Varsig) string {
+	v = unwrapAny(v)
+	if v == nil {
+		return ""
+	}
+
+	if data, ok := tryEncode(v); ok {
+		return string(data)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(invalidKeyPrefix)
+
+	d, ok := v.(Describer)
+	if !ok {
+		fmt.Fprintf(&sb, "%T%#v", v, v)
+
+		return sb.String()
+	}
+
+	for _, p := range d.Describe() {
+		sb.WriteString(p.Name)
+		sb.WriteByte('=')
+
+		// Invalid parameters all have a zero value, so they're told
+		// apart by their text.
+		if p.Invalid {
+			sb.WriteString(strconv.Quote(p.Text))
+		} else {
+			sb.WriteString(strconv.FormatUint(p.Value, 16))
+		}
+
+		sb.WriteByte(';')
+	}
+
+	return sb.String()
+}
+
+// Equal returns true if both varsigs describe the same signature
+// parameters, regardless of their concrete types.
+func Equal(a, b Varsig) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return Key(a) == Key(b)
+}
+
+// unwrapAny returns the Varsig held by v if it's an Any, recursively.
+func unwrapAny(v Varsig) Varsig {
+	for {
+		switch a := v.(type) {
+		case Any:
+			v = a.Varsig
+		case *Any:
+			if a == nil {
+				return nil
+			}
+
+			v = a.Varsig
+		default:
+			return v
+		}
+	}
+}
+
+// tryEncode returns the encoded varsig, or false if it can't be encoded.
+func tryEncode(v Varsig) (data []byte, ok bool) {
+	defer func() {
+		if recover() != nil {
+			data, ok = nil, false
+		}
+	}()
+
+	data, err := encodeVarsig(v)
+
+	return data, err == nil && len(data) > 0
+}
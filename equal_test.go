@@ -0,0 +1,105 @@
+package varsig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	es256 := varsig.ES256(varsig.PayloadEncodingDAGCBOR)
+
+	for _, tc := range []struct {
+		name  string
+		a, b  varsig.Varsig
+		equal bool
+	}{
+		{name: "same", a: es256, b: varsig.NewECDSAVarsig(varsig.CurveP256, varsig.HashSha2_256, varsig.PayloadEncodingDAGCBOR), equal: true},
+		{name: "same as Any", a: es256, b: varsig.Any{Varsig: es256}, equal: true},
+		{name: "same as registry-provided type", a: es256, b: encodedVarsig{es256}, equal: true},
+		{name: "different payload encoding", a: es256, b: varsig.ES256(varsig.PayloadEncodingDAGJSON), equal: false},
+		{name: "different curve", a: es256, b: varsig.ES256K(varsig.PayloadEncodingDAGCBOR), equal: false},
+		{name: "different key length", a: varsig.RS256(256, varsig.PayloadEncodingVerbatim), b: varsig.RS256(512, varsig.PayloadEncodingVerbatim), equal: false},
+		{name: "both nil", a: nil, b: nil, equal: true},
+		{name: "one nil", a: es256, b: nil, equal: false},
+		{
+			name:  "invalid payload encodings",
+			a:     varsig.ES256(varsig.PayloadEncodingUnspecified),
+			b:     varsig.ES256(varsig.PayloadEncodingUnspecified),
+			equal: true,
+		},
+		{
+			name:  "invalid payload encodings with different curves",
+			a:     varsig.ES256(varsig.PayloadEncodingUnspecified),
+			b:     varsig.ES384(varsig.PayloadEncodingUnspecified),
+			equal: false,
+		},
+		{
+			name:  "different invalid payload encodings",
+			a:     varsig.ES256(varsig.PayloadEncodingUnspecified),
+			b:     varsig.ES256(varsig.PayloadEncoding(42)),
+			equal: false,
+		},
+		{
+			name:  "invalid payload encodings as Any",
+			a:     varsig.ES256(varsig.PayloadEncodingUnspecified),
+			b:     varsig.Any{Varsig: varsig.ES256(varsig.PayloadEncodingUnspecified)},
+			equal: true,
+		},
+		{
+			name:  "invalid payload encodings as Any with different curves",
+			a:     varsig.Any{Varsig: varsig.ES256(varsig.PayloadEncodingUnspecified)},
+			b:     varsig.Any{Varsig: varsig.ES384(varsig.PayloadEncodingUnspecified)},
+			equal: false,
+		},
+		{
+			name:  "invalid payload encodings of registry-provided types with different curves",
+			a:     encodedVarsig{varsig.ES256(varsig.PayloadEncodingUnspecified)},
+			b:     encodedVarsig{varsig.NewECDSAVarsig(varsig.CurveP384, varsig.HashSha2_256, varsig.PayloadEncodingUnspecified)},
+			equal: false,
+		},
+		{
+			name:  "Encode panics",
+			a:     encodedVarsig{varsig.ES256(varsig.PayloadEncoding(42))},
+			b:     encodedVarsig{varsig.ES256(varsig.PayloadEncoding(42))},
+			equal: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.equal, varsig.Equal(tc.a, tc.b))
+			assert.Equal(t, tc.equal, varsig.Equal(tc.b, tc.a))
+		})
+	}
+
+	t.Run("Key", func(t *testing.T) {
+		t.Parallel()
+
+		cache := map[string]int{}
+		cache[varsig.Key(es256)]++
+		cache[varsig.Key(varsig.ES256(varsig.PayloadEncodingDAGCBOR))]++
+		cache[varsig.Key(varsig.ES256(varsig.PayloadEncodingVerbatim))]++
+		cache[varsig.Key(varsig.ES256(varsig.PayloadEncodingUnspecified))]++
+
+		require.Len(t, cache, 3)
+		require.Equal(t, 2, cache[varsig.Key(es256)])
+	})
+}
+
+// encodedVarsig is a Varsig type that isn't provided by this library and
+// doesn't implement Describer.
+type encodedVarsig struct {
+	vs varsig.ECDSAVarsig
+}
+
+func (v encodedVarsig) Version() varsig.Version                 { return v.vs.Version() }
+func (v encodedVarsig) Algorithm() varsig.Algorithm             { return v.vs.Algorithm() }
+func (v encodedVarsig) Hash() varsig.Hash                       { return v.vs.Hash() }
+func (v encodedVarsig) PayloadEncoding() varsig.PayloadEncoding { return v.vs.PayloadEncoding() }
+func (v encodedVarsig) Encode() []byte                          { return v.vs.Encode() }
@@ -1,6 +1,9 @@
 package varsig
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrNotYetImplemented is returned when a function is currently under
 // construction.  For released versions of this library, this error should
@@ -35,3 +38,82 @@ var ErrUnsupportedVersion = errors.New("unsupported version")
 // ErrBadPrefix is returned when the prefix field contains a value other
 // than 0x34 (encoded as an uvarint).
 var ErrBadPrefix = errors.New("varsig prefix not found")
+
+// ErrNonCanonicalVarint is returned when a decoded uvarint isn't minimally
+// encoded (see AllowNonCanonical.)
+var ErrNonCanonicalVarint = errors.New("non-canonical varint")
+
+// ErrLimitExceeded is returned when decoding a varsig exceeds one of the
+// Limits set with LimitReader.
+var ErrLimitExceeded = errors.New("decoding limit exceeded")
+
+// ErrPolicyViolation is returned when a decoded varsig is rejected by the
+// policy set with WithPolicy.
+var ErrPolicyViolation = errors.New("varsig rejected by policy")
+
+// ErrTrailingBytes is returned when strictly decoding data that continues
+// after the varsig.
+var ErrTrailingBytes = errors.New("trailing bytes after varsig")
+
+// ErrUnsupportedCOSEAlgorithm is returned when a COSE algorithm can't be
+// converted to a Varsig, or when a Varsig has no COSE equivalent.
+var ErrUnsupportedCOSEAlgorithm = errors.New("unsupported COSE algorithm")
+
+// ErrInvalidWebAuthnAssertion is returned when the WebAuthn assertion
+// data is malformed or isn't bound to the signed payload.
+var ErrInvalidWebAuthnAssertion = errors.New("invalid WebAuthn assertion")
+
+// ErrInvalidEIP712TypedData is returned when EIP-712 typed data can't be
+// hashed because its values don't match the types in its schema.
+var ErrInvalidEIP712TypedData = errors.New("invalid EIP-712 typed data")
+
+// ErrBadDescriptor is returned when a textual varsig descriptor can't be
+// parsed.
+var ErrBadDescriptor = errors.New("malformed varsig descriptor")
+
+// ErrUnsupportedMultibase is returned when a multibase string uses an
+// unsupported base or isn't correctly encoded.
+var ErrUnsupportedMultibase = errors.New("unsupported multibase")
+
+// ErrNilVarsig is returned when a nil Varsig (for instance an empty Any)
+// is marshaled.
+var ErrNilVarsig = errors.New("nil varsig")
+
+// ErrBadIPLDRepresentation is returned when the DAG-CBOR or DAG-JSON
+// representation of a varsig isn't a valid bytes kind.
+var ErrBadIPLDRepresentation = errors.New("malformed IPLD representation")
+
+// DecodeError describes the varsig field that couldn't be decoded.  It
+// wraps the errors above, so errors.Is can still be used to check the
+// reason of the failure.
+type DecodeError struct {
+	// Field is the name of the field (ParameterPrefix, ParameterVersion,
+	// ParameterHash, etc.)
+	Field string
+
+	// Offset is the position of the field's first byte, counted from
+	// where decoding started.
+	Offset int
+
+	// Value is the field's raw (multicodec) value, or zero if it couldn't
+	// be read.
+	Value uint64
+
+	// Err is the underlying error.
+	Err error
+}
+
+func decodeError(field string, offset int, value uint64, err error) error {
+	return &DecodeError{Field: field, Offset: offset, Value: value, Err: err}
+}
+
+// Error returns the underlying error's message prefixed by the field and
+// its offset.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %v", e.Field, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,101 @@
+package varsig_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDecodeError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails - fields", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name     string
+			hex      string
+			field    string
+			offset   int
+			value    uint64
+			sentinel error
+		}{
+			{name: "empty", hex: "", field: varsig.ParameterPrefix, sentinel: io.EOF},
+			{name: "wrong prefix", hex: "42", field: varsig.ParameterPrefix, value: 0x42, sentinel: varsig.ErrBadPrefix},
+			{name: "unsupported version", hex: "3402", field: varsig.ParameterVersion, offset: 1, value: 2, sentinel: varsig.ErrUnsupportedVersion},
+			{name: "v0 header", hex: "34ed01ed011371", field: varsig.ParameterVersion, offset: 1, sentinel: varsig.ErrUnsupportedVersion},
+			{name: "no algorithm", hex: "3401", field: varsig.ParameterAlgorithm, offset: 2, sentinel: io.EOF},
+			{name: "unknown algorithm", hex: "3401b260", field: varsig.ParameterAlgorithm, offset: 2, value: 0x3032, sentinel: varsig.ErrUnknownAlgorithm},
+			{name: "unknown curve", hex: "3401ec01e801", field: varsig.ParameterCurve, offset: 4, value: 0xe8, sentinel: varsig.ErrUnknownECDSACurve},
+			{name: "unknown hash", hex: "3401ed01ed01e1e402", field: varsig.ParameterHash, offset: 6, value: 0xb261, sentinel: varsig.ErrUnknownHash},
+			{name: "non-canonical hash", hex: "3401ed01ed019300", field: varsig.ParameterHash, offset: 6, sentinel: varsig.ErrNonCanonicalVarint},
+			{name: "truncated key length", hex: "34018524128080", field: varsig.ParameterKeyLength, offset: 5, sentinel: io.ErrUnexpectedEOF},
+			{name: "unsupported payload encoding", hex: "3401ed01ed011342", field: varsig.ParameterPayloadEncoding, offset: 7, value: 0x42, sentinel: varsig.ErrUnsupportedPayloadEncoding},
+			{name: "unsupported inner payload encoding", hex: "3401ec01e7011b91c30370", field: varsig.ParameterPayloadEncodingInner, offset: 10, value: 0x70, sentinel: varsig.ErrUnsupportedPayloadEncoding},
+			{name: "no inner payload encoding", hex: "3401ec01e7011b91c303", field: varsig.ParameterPayloadEncodingInner, offset: 10, sentinel: io.EOF},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data := mustDecodeHex(t, tc.hex)
+
+				_, streamErr := varsig.Decode(data)
+				_, _, bytesErr := varsig.DecodeBytes(data)
+
+				for _, err := range []error{streamErr, bytesErr} {
+					require.ErrorIs(t, err, tc.sentinel)
+
+					var decErr *varsig.DecodeError
+					require.ErrorAs(t, err, &decErr)
+					assert.Equal(t, tc.field, decErr.Field)
+					assert.Equal(t, tc.offset, decErr.Offset)
+					assert.Equal(t, tc.value, decErr.Value)
+				}
+			})
+		}
+	})
+
+	t.Run("fails - message", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.Decode(mustDecodeHex(t, "3401ed01ed01e1e40271"))
+		require.EqualError(t, err, "hash at offset 6: unknown hash algorithm: 0xb261")
+	})
+
+	t.Run("fails - offsets are relative to each varsig", func(t *testing.T) {
+		t.Parallel()
+
+		data := append(varsig.Ed25519(varsig.PayloadEncodingDAGCBOR).Encode(), mustDecodeHex(t, "3401ed01ed011342")...)
+		r := bytes.NewReader(data)
+
+		_, err := varsig.DecodeStream(r)
+		require.NoError(t, err)
+
+		_, err = varsig.DecodeStream(r)
+
+		var decErr *varsig.DecodeError
+		require.True(t, errors.As(err, &decErr))
+		assert.Equal(t, 7, decErr.Offset)
+	})
+
+	t.Run("fails - field decoding functions", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.DecodeHashAlgorithm(bytes.NewReader([]byte{0x42}))
+
+		var decErr *varsig.DecodeError
+		require.ErrorAs(t, err, &decErr)
+		assert.Equal(t, &varsig.DecodeError{
+			Field: varsig.ParameterHash,
+			Value: 0x42,
+			Err:   decErr.Err,
+		}, decErr)
+		require.ErrorIs(t, err, varsig.ErrUnknownHash)
+	})
+}
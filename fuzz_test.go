@@ -0,0 +1,211 @@
+package varsig_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// fuzzSeeds is the seed corpus shared by the fuzz targets.  It's derived
+// from the test vectors in varsig_test.go, rsa_test.go and eddsa_test.go,
+// along with the encodings of appendVarsigs.
+func fuzzSeeds(f *testing.F) [][]byte {
+	f.Helper()
+
+	var seeds [][]byte
+
+	for _, s := range []string{
+		"NAHtAe0BE3E", // UCAN v1.0.0 example (eddsa_test.go)
+		"NAGFJBKAAnE", // RSA v1 example (rsa_test.go)
+	} {
+		data, err := base64.RawStdEncoding.DecodeString(s)
+		require.NoError(f, err)
+
+		seeds = append(seeds, data)
+	}
+
+	for _, s := range []string{
+		// Section 3 example (v0 header)
+		"34ed01ae3784f03f9ee1163382fa6efa73b0c31ecf58c899c836709303ba4621d1e6df20e09aaa568914290b7ea124f5b38e70b9b69c7de0d216880eac885edd41c302",
+		"",
+		"42",
+		"3402",
+		"340164",
+		"34018524128002",
+		"340185241280025f",
+		"3401852412800242",
+		"3401ed01ed011371e5564300c360ac72",
+	} {
+		data, err := hex.DecodeString(s)
+		require.NoError(f, err)
+
+		seeds = append(seeds, data)
+	}
+
+	for _, vs := range appendVarsigs {
+		seeds = append(seeds, vs.Encode())
+	}
+
+	return seeds
+}
+
+// fuzzFields returns the encoded hash algorithms and payload encodings of
+// the seeds that can be decoded, to seed the field decoders.
+func fuzzFields(f *testing.F) (hashes, payloadEncodings [][]byte) {
+	f.Helper()
+
+	for _, data := range fuzzSeeds(f) {
+		vs, err := varsig.Decode(data)
+		if err != nil {
+			continue
+		}
+
+		hashes = append(hashes, binary.AppendUvarint(nil, uint64(vs.Hash())))
+
+		payloadEncodings = append(payloadEncodings, varsig.EncodePayloadEncoding(vs.PayloadEncoding()))
+	}
+
+	return hashes, payloadEncodings
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+
+		vs, err := varsig.DecodeStream(r)
+		fast, n, fastErr := varsig.DecodeBytes(data)
+
+		// Decoding streams and byte slices must agree.
+		if err != nil {
+			assert.Nil(t, vs)
+			require.EqualError(t, fastErr, err.Error())
+			assert.Nil(t, fast)
+
+			return
+		}
+
+		require.NoError(t, fastErr)
+		assert.Equal(t, vs, fast)
+		assert.Equal(t, len(data)-r.Len(), n)
+
+		// Non-canonical varints are rejected, so the decoded bytes are
+		// the only encoding of the varsig.
+		assert.Equal(t, data[:n], vs.Encode())
+
+		slow, err := varsig.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, vs, slow)
+	})
+}
+
+func FuzzDecodeHashAlgorithm(f *testing.F) {
+	hashes, _ := fuzzFields(f)
+	for _, seed := range hashes {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+
+		h, err := varsig.DecodeHashAlgorithm(r)
+		if err != nil {
+			assert.Equal(t, varsig.HashUnspecified, h)
+			return
+		}
+
+		assert.Equal(t, data[:len(data)-r.Len()], binary.AppendUvarint(nil, uint64(h)))
+	})
+}
+
+func FuzzDecodePayloadEncoding(f *testing.F) {
+	_, payloadEncodings := fuzzFields(f)
+	for _, seed := range payloadEncodings {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+
+		enc, err := varsig.DecodePayloadEncoding(r)
+		if err != nil {
+			assert.Equal(t, varsig.PayloadEncodingUnspecified, enc)
+			return
+		}
+
+		assert.Equal(t, data[:len(data)-r.Len()], varsig.EncodePayloadEncoding(enc))
+	})
+}
+
+func FuzzEdDSAVarsig(f *testing.F) {
+	fuzzAlgorithm(f, varsig.AlgorithmEdDSA, func(data []byte) (varsig.Varsig, int, error) {
+		var v varsig.EdDSAVarsig
+		n, err := v.DecodeBytes(data)
+
+		return v, n, err
+	})
+}
+
+func FuzzECDSAVarsig(f *testing.F) {
+	fuzzAlgorithm(f, varsig.AlgorithmECDSA, func(data []byte) (varsig.Varsig, int, error) {
+		var v varsig.ECDSAVarsig
+		n, err := v.DecodeBytes(data)
+
+		return v, n, err
+	})
+}
+
+func FuzzRSAVarsig(f *testing.F) {
+	fuzzAlgorithm(f, varsig.AlgorithmRSA, func(data []byte) (varsig.Varsig, int, error) {
+		var v varsig.RSAVarsig
+		n, err := v.DecodeBytes(data)
+
+		return v, n, err
+	})
+}
+
+// fuzzAlgorithm checks a single algorithm's decoders: the DecodeBytes
+// method and the DecodeFunc from the DefaultRegistry, on its own in a
+// Registry.
+func fuzzAlgorithm(f *testing.F, algo varsig.Algorithm, decodeBytes func([]byte) (varsig.Varsig, int, error)) {
+	f.Helper()
+
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	rs := varsig.NewRegistry()
+	rs.Register(algo, varsig.DefaultRegistry()[algo])
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		vs, rest, err := rs.DecodePrefix(data)
+		if err != nil {
+			assert.Nil(t, vs)
+
+			_, _, err = decodeBytes(data)
+			require.Error(t, err)
+
+			return
+		}
+
+		assert.Equal(t, algo, vs.Algorithm())
+
+		n := len(data) - len(rest)
+		assert.Equal(t, data[:n], vs.Encode())
+
+		fast, fastN, err := decodeBytes(data)
+		require.NoError(t, err)
+		assert.Equal(t, vs, fast)
+		assert.Equal(t, n, fastN)
+	})
+}
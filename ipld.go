@@ -0,0 +1,171 @@
+package varsig
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// In IPLD data (such as UCAN envelopes), the varsig header is represented
+// using the bytes kind - in DAG-CBOR this is a CBOR byte string (major
+// type 2) while in DAG-JSON this is the reserved {"/":{"bytes":"..."}}
+// object containing the unpadded standard base64 representation of the
+// bytes.
+//
+// See https://ipld.io/specs/codecs/dag-cbor/spec/ and
+// https://ipld.io/specs/codecs/dag-json/spec/
+
+// cborMajorTypeBytes is the CBOR major type of byte strings.
+const cborMajorTypeBytes = 2
+
+// MarshalDAGCBOR returns the varsig represented as a DAG-CBOR byte string.
+func MarshalDAGCBOR(v Varsig) ([]byte, error) {
+	data, err := encodeVarsig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, len(data)+9)
+	buf = appendCBORHead(buf, cborMajorTypeBytes, uint64(len(data)))
+
+	return append(buf, data...), nil
+}
+
+// UnmarshalDAGCBOR converts the provided DAG-CBOR byte string into one of
+// the Varsig types provided by the DefaultRegistry.
+func UnmarshalDAGCBOR(data []byte, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().UnmarshalDAGCBOR(data, opts...)
+}
+
+// UnmarshalDAGCBOR converts the provided DAG-CBOR byte string into one of
+// the registered Varsig types, with the behavior changed by the provided
+// options.  The byte string must hold exactly one varsig (see
+// ErrTrailingBytes.)  The WebAuthn payload encodings are accepted (see
+// WithWebAuthn.)
+func (rs Registry) UnmarshalDAGCBOR(data []byte, opts ...DecodeOption) (Varsig, error) {
+	major, n, rest, err := readCBORHead(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if major != cborMajorTypeBytes {
+		return nil, fmt.Errorf("%w: expected CBOR byte string, got major type %d", ErrBadIPLDRepresentation, major)
+	}
+
+	if uint64(len(rest)) != n {
+		return nil, fmt.Errorf("%w: byte string length is %d but %d bytes remain", ErrBadIPLDRepresentation, n, len(rest))
+	}
+
+	return rs.DecodeStrict(rest, unmarshalOptions(opts)...)
+}
+
+// dagJSONBytes is the DAG-JSON representation of the bytes kind.
+type dagJSONBytes struct {
+	Slash struct {
+		Bytes string `json:"bytes"`
+	} `json:"/"`
+}
+
+// MarshalDAGJSON returns the varsig represented as DAG-JSON bytes.
+func MarshalDAGJSON(v Varsig) ([]byte, error) {
+	data, err := encodeVarsig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var node dagJSONBytes
+	node.Slash.Bytes = base64.RawStdEncoding.EncodeToString(data)
+
+	return json.Marshal(node)
+}
+
+// UnmarshalDAGJSON converts the provided DAG-JSON bytes into one of the
+// Varsig types provided by the DefaultRegistry.
+func UnmarshalDAGJSON(data []byte, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().UnmarshalDAGJSON(data, opts...)
+}
+
+// UnmarshalDAGJSON converts the provided DAG-JSON bytes into one of the
+// registered Varsig types, with the behavior changed by the provided
+// options.  The bytes must hold exactly one varsig (see
+// ErrTrailingBytes.)  The WebAuthn payload encodings are accepted (see
+// WithWebAuthn.)
+func (rs Registry) UnmarshalDAGJSON(data []byte, opts ...DecodeOption) (Varsig, error) {
+	var outer map[string]map[string]string
+	if err := json.Unmarshal(data, &outer); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadIPLDRepresentation, err)
+	}
+
+	inner, ok := outer["/"]
+	if !ok || len(outer) != 1 {
+		return nil, fmt.Errorf("%w: expected a single \"/\" key", ErrBadIPLDRepresentation)
+	}
+
+	b64, ok := inner["bytes"]
+	if !ok || len(inner) != 1 {
+		return nil, fmt.Errorf("%w: expected a single \"bytes\" key", ErrBadIPLDRepresentation)
+	}
+
+	vsData, err := base64.RawStdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadIPLDRepresentation, err)
+	}
+
+	return rs.DecodeStrict(vsData, unmarshalOptions(opts)...)
+}
+
+// appendCBORHead appends the (shortest) CBOR head for a data item of the
+// major type with the argument n.
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= 0xff:
+		return append(buf, major|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// readCBORHead reads the head of a CBOR data item, returning its major
+// type, its argument and the remaining data.  As required by DAG-CBOR,
+// the argument must be encoded in its shortest form and indefinite
+// lengths are rejected.
+func readCBORHead(data []byte) (byte, uint64, []byte, error) {
+	if len(data) == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: empty CBOR data", ErrBadIPLDRepresentation)
+	}
+
+	major, info, data := data[0]>>5, data[0]&0x1f, data[1:]
+
+	if info < 24 {
+		return major, uint64(info), data, nil
+	}
+
+	if info > 27 {
+		return 0, 0, nil, fmt.Errorf("%w: unsupported CBOR additional information %d", ErrBadIPLDRepresentation, info)
+	}
+
+	size := 1 << (info - 24)
+	if len(data) < size {
+		return 0, 0, nil, fmt.Errorf("%w: truncated CBOR head", ErrBadIPLDRepresentation)
+	}
+
+	var arg [8]byte
+	copy(arg[8-size:], data[:size])
+	n := binary.BigEndian.Uint64(arg[:])
+
+	// The shortest form of the argument has the same size as its head.
+	if len(appendCBORHead(nil, major, n)) != 1+size {
+		return 0, 0, nil, fmt.Errorf("%w: non-canonical CBOR head", ErrBadIPLDRepresentation)
+	}
+
+	return major, n, data[size:], nil
+}
@@ -0,0 +1,144 @@
+package varsig_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDAGCBOR(t *testing.T) {
+	t.Parallel()
+
+	vs := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := varsig.MarshalDAGCBOR(vs)
+		require.NoError(t, err)
+		require.Equal(t, "483401ed01ed011371", hex.EncodeToString(data))
+
+		rt, err := varsig.UnmarshalDAGCBOR(data)
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+	})
+
+	t.Run("passes - non-canonical", func(t *testing.T) {
+		t.Parallel()
+
+		data := mustDecodeHex(t, "493401ed01ed01930071")
+
+		_, err := varsig.UnmarshalDAGCBOR(data)
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		rt, err := varsig.UnmarshalDAGCBOR(data, varsig.WithNonCanonical())
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+	})
+
+	t.Run("fails - trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		rt, err := varsig.UnmarshalDAGCBOR(mustDecodeHex(t, "4a3401ed01ed011371dead"))
+		require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+		require.Nil(t, rt)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name    string
+			dataHex string
+		}{
+			{name: "empty", dataHex: ""},
+			{name: "text string", dataHex: "683401ed01ed011371"},
+			{name: "non-canonical length", dataHex: "58083401ed01ed011371"},
+			{name: "indefinite length", dataHex: "5f483401ed01ed011371ff"},
+			{name: "truncated head", dataHex: "59"},
+			{name: "truncated bytes", dataHex: "483401ed01ed0113"},
+			{name: "trailing bytes", dataHex: "483401ed01ed01137100"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data, err := hex.DecodeString(tc.dataHex)
+				require.NoError(t, err)
+
+				rt, err := varsig.UnmarshalDAGCBOR(data)
+				require.ErrorIs(t, err, varsig.ErrBadIPLDRepresentation)
+				require.Nil(t, rt)
+			})
+		}
+	})
+}
+
+func TestDAGJSON(t *testing.T) {
+	t.Parallel()
+
+	vs := varsig.RS256(0x100, varsig.PayloadEncodingDAGCBOR)
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := varsig.MarshalDAGJSON(vs)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"/":{"bytes":"NAGFJBKAAnE"}}`, string(data))
+
+		rt, err := varsig.UnmarshalDAGJSON(data)
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+
+		rt, err = testRegistry(t).UnmarshalDAGJSON([]byte(`{"/":{"bytes":"NAGBIA"}}`))
+		require.NoError(t, err)
+		require.Equal(t, testAlgorithm1, rt.Algorithm())
+	})
+
+	t.Run("passes - non-canonical", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"/":{"bytes":"NAHtAe0BkwBx"}}`)
+
+		_, err := varsig.UnmarshalDAGJSON(data)
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		rt, err := varsig.UnmarshalDAGJSON(data, varsig.WithNonCanonical())
+		require.NoError(t, err)
+		require.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), rt)
+	})
+
+	t.Run("fails - trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		rt, err := varsig.UnmarshalDAGJSON([]byte(`{"/":{"bytes":"NAGFJBKAAnHerQ"}}`))
+		require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+		require.Nil(t, rt)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name string
+			json string
+		}{
+			{name: "not JSON", json: `{`},
+			{name: "string", json: `"NAGFJBKAAnE"`},
+			{name: "CID link", json: `{"/":"bafkqaaa"}`},
+			{name: "extra key", json: `{"/":{"bytes":"NAGFJBKAAnE"},"x":{}}`},
+			{name: "extra inner key", json: `{"/":{"bytes":"NAGFJBKAAnE","x":""}}`},
+			{name: "padded base64", json: `{"/":{"bytes":"NAGFJBKAAnE="}}`},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				rt, err := varsig.UnmarshalDAGJSON([]byte(tc.json))
+				require.ErrorIs(t, err, varsig.ErrBadIPLDRepresentation)
+				require.Nil(t, rt)
+			})
+		}
+	})
+}
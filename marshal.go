@@ -0,0 +1,279 @@
+package varsig
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+)
+
+// The Varsig implementations provided by this library can be used
+// directly as fields of structs that are (un)marshaled using the
+// standard library.  The binary format is the encoded varsig, while
+// the text format is the encoded varsig as a multibase string (using
+// base64url when marshaling.)  The JSON format is a string containing
+// the text format.  When unmarshaling, the data must hold exactly one
+// varsig: trailing bytes are rejected with ErrTrailingBytes.  The WebAuthn
+// payload encodings are accepted (see WithWebAuthn.)
+//
+// Any can be used to marshal fields that may contain any Varsig.  The
+// Varsig types reject uvarints that aren't minimally encoded when
+// unmarshaling; an Any with the WithNonCanonical option accepts them.
+
+var (
+	_ encoding.BinaryMarshaler   = EdDSAVarsig{}
+	_ encoding.BinaryUnmarshaler = (*EdDSAVarsig)(nil)
+	_ encoding.TextMarshaler     = EdDSAVarsig{}
+	_ encoding.TextUnmarshaler   = (*EdDSAVarsig)(nil)
+	_ json.Marshaler             = EdDSAVarsig{}
+	_ json.Unmarshaler           = (*EdDSAVarsig)(nil)
+
+	_ encoding.BinaryMarshaler   = ECDSAVarsig{}
+	_ encoding.BinaryUnmarshaler = (*ECDSAVarsig)(nil)
+	_ encoding.TextMarshaler     = ECDSAVarsig{}
+	_ encoding.TextUnmarshaler   = (*ECDSAVarsig)(nil)
+	_ json.Marshaler             = ECDSAVarsig{}
+	_ json.Unmarshaler           = (*ECDSAVarsig)(nil)
+
+	_ encoding.BinaryMarshaler   = RSAVarsig{}
+	_ encoding.BinaryUnmarshaler = (*RSAVarsig)(nil)
+	_ encoding.TextMarshaler     = RSAVarsig{}
+	_ encoding.TextUnmarshaler   = (*RSAVarsig)(nil)
+	_ json.Marshaler             = RSAVarsig{}
+	_ json.Unmarshaler           = (*RSAVarsig)(nil)
+
+	_ Varsig                     = Any{}
+	_ encoding.BinaryMarshaler   = Any{}
+	_ encoding.BinaryUnmarshaler = (*Any)(nil)
+	_ encoding.TextMarshaler     = Any{}
+	_ encoding.TextUnmarshaler   = (*Any)(nil)
+	_ json.Marshaler             = Any{}
+	_ json.Unmarshaler           = (*Any)(nil)
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v EdDSAVarsig) MarshalBinary() ([]byte, error) {
+	return marshalBinary(v)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *EdDSAVarsig) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, AlgorithmEdDSA, decodeEdDSA, v)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v EdDSAVarsig) MarshalText() ([]byte, error) {
+	return marshalText(v)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *EdDSAVarsig) UnmarshalText(text []byte) error {
+	return unmarshalText(text, v)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v EdDSAVarsig) MarshalJSON() ([]byte, error) {
+	return marshalJSON(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *EdDSAVarsig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, v)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v ECDSAVarsig) MarshalBinary() ([]byte, error) {
+	return marshalBinary(v)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *ECDSAVarsig) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, AlgorithmECDSA, decodeECDSA, v)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v ECDSAVarsig) MarshalText() ([]byte, error) {
+	return marshalText(v)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *ECDSAVarsig) UnmarshalText(text []byte) error {
+	return unmarshalText(text, v)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v ECDSAVarsig) MarshalJSON() ([]byte, error) {
+	return marshalJSON(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *ECDSAVarsig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, v)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v RSAVarsig) MarshalBinary() ([]byte, error) {
+	return marshalBinary(v)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *RSAVarsig) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, AlgorithmRSA, decodeRSA, v)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v RSAVarsig) MarshalText() ([]byte, error) {
+	return marshalText(v)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *RSAVarsig) UnmarshalText(text []byte) error {
+	return unmarshalText(text, v)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v RSAVarsig) MarshalJSON() ([]byte, error) {
+	return marshalJSON(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *RSAVarsig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, v)
+}
+
+// Any holds any Varsig so that it can be (un)marshaled, for instance as
+// the field of a struct.  When unmarshaling, the Varsig is decoded using
+// the Registry, or the DefaultRegistry if the Registry is nil, with the
+// behavior changed by the Options.
+type Any struct {
+	Varsig
+
+	Registry Registry
+	Options  []DecodeOption
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (a Any) MarshalBinary() ([]byte, error) {
+	return encodeVarsig(a.Varsig)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (a *Any) UnmarshalBinary(data []byte) error {
+	reg := a.Registry
+	if reg == nil {
+		reg = DefaultRegistry()
+	}
+
+	vs, err := reg.DecodeStrict(data, unmarshalOptions(a.Options)...)
+	if err != nil {
+		return err
+	}
+
+	a.Varsig = vs
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Any) MarshalText() ([]byte, error) {
+	return marshalText(a)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Any) UnmarshalText(text []byte) error {
+	return unmarshalText(text, a)
+}
+
+// MarshalJSON implements json.Marshaler.  An Any without a Varsig is
+// marshaled as null.
+func (a Any) MarshalJSON() ([]byte, error) {
+	if a.Varsig == nil {
+		return []byte("null"), nil
+	}
+
+	return marshalJSON(a)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Any) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, a)
+}
+
+// encodeVarsig returns the encoded varsig, using its MarshalBinary
+// method when available so that invalid varsigs don't panic.
+func encodeVarsig(v Varsig) ([]byte, error) {
+	if v == nil {
+		return nil, ErrNilVarsig
+	}
+
+	if m, ok := v.(encoding.BinaryMarshaler); ok {
+		return m.MarshalBinary()
+	}
+
+	return v.Encode(), nil
+}
+
+func marshalBinary(v Varsig) ([]byte, error) {
+	if !validPayloadEncoding(v.PayloadEncoding()) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedPayloadEncoding, v.PayloadEncoding())
+	}
+
+	return v.Encode(), nil
+}
+
+func unmarshalBinary[T Varsig](data []byte, algo Algorithm, decodeFunc DecodeFunc, dst *T) error {
+	vs, err := Registry{algo: decodeFunc}.DecodeStrict(data, unmarshalOptions(nil)...)
+	if err != nil {
+		return err
+	}
+
+	*dst = vs.(T)
+
+	return nil
+}
+
+func marshalText(m encoding.BinaryMarshaler) ([]byte, error) {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := encodeMultibase(MultibaseBase64URL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(text), nil
+}
+
+func unmarshalText(text []byte, u encoding.BinaryUnmarshaler) error {
+	data, err := decodeMultibase(string(text))
+	if err != nil {
+		return err
+	}
+
+	return u.UnmarshalBinary(data)
+}
+
+func marshalJSON(m encoding.TextMarshaler) ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+func unmarshalJSON(data []byte, u encoding.TextUnmarshaler) error {
+	// By convention, unmarshaling null is a no-op.
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	return u.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,143 @@
+package varsig_test
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EdDSAVarsig", func(t *testing.T) {
+		t.Parallel()
+
+		vs := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+
+		data, err := vs.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, vs.Encode(), data)
+
+		var rt varsig.EdDSAVarsig
+		require.NoError(t, rt.UnmarshalBinary(data))
+		require.Equal(t, vs, rt)
+
+		text, err := vs.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, "uNAHtAe0BE3E", string(text))
+
+		rt = varsig.EdDSAVarsig{}
+		require.NoError(t, rt.UnmarshalText([]byte("z9hYDEAqjN5e")))
+		require.Equal(t, vs, rt)
+	})
+
+	t.Run("JSON struct fields", func(t *testing.T) {
+		t.Parallel()
+
+		type header struct {
+			EdDSA varsig.EdDSAVarsig `json:"eddsa"`
+			ECDSA varsig.ECDSAVarsig `json:"ecdsa"`
+			RSA   varsig.RSAVarsig   `json:"rsa"`
+			Any   varsig.Any         `json:"any"`
+			None  varsig.Any         `json:"none"`
+		}
+
+		h := header{
+			EdDSA: varsig.Ed25519(varsig.PayloadEncodingDAGCBOR),
+			ECDSA: varsig.ES256(varsig.PayloadEncodingVerbatim),
+			RSA:   varsig.RS256(0x100, varsig.PayloadEncodingDAGCBOR),
+			Any:   varsig.Any{Varsig: varsig.ES256K(varsig.PayloadEncodingDAGJSON)},
+		}
+
+		data, err := json.Marshal(h)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"eddsa": "uNAHtAe0BE3E",
+			"ecdsa": "uNAHsAYAkEl8",
+			"rsa": "uNAGFJBKAAnE",
+			"any": "uNAHsAecBEqkC",
+			"none": null
+		}`, string(data))
+
+		var rt header
+		require.NoError(t, json.Unmarshal(data, &rt))
+		require.Equal(t, h, rt)
+	})
+
+	t.Run("Any with custom registry", func(t *testing.T) {
+		t.Parallel()
+
+		a := varsig.Any{Registry: testRegistry(t)}
+		require.NoError(t, a.UnmarshalText([]byte("uNAGBIA")))
+		require.Equal(t, testAlgorithm1, a.Algorithm())
+
+		err := (&varsig.Any{}).UnmarshalText([]byte("uNAGBIA"))
+		require.ErrorIs(t, err, varsig.ErrUnknownAlgorithm)
+	})
+
+	t.Run("Any with non-canonical option", func(t *testing.T) {
+		t.Parallel()
+
+		err := (&varsig.Any{}).UnmarshalText([]byte("uNAHtAe0BkwBx"))
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		a := varsig.Any{Options: []varsig.DecodeOption{varsig.WithNonCanonical()}}
+		require.NoError(t, a.UnmarshalText([]byte("uNAHtAe0BkwBx")))
+		require.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), a.Varsig)
+	})
+
+	t.Run("fails - wrong algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		var vs varsig.ECDSAVarsig
+		err := vs.UnmarshalText([]byte("uNAHtAe0BE3E"))
+		require.ErrorIs(t, err, varsig.ErrUnknownAlgorithm)
+	})
+
+	t.Run("fails - invalid payload encoding", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.RS256(0x100, varsig.PayloadEncodingUnspecified).MarshalText()
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+	})
+
+	t.Run("fails - unsupported multibase", func(t *testing.T) {
+		t.Parallel()
+
+		var vs varsig.RSAVarsig
+		require.ErrorIs(t, vs.UnmarshalText([]byte("NAGFJBKAAnE")), varsig.ErrUnsupportedMultibase)
+		require.ErrorIs(t, vs.UnmarshalText([]byte("")), varsig.ErrUnsupportedMultibase)
+		require.ErrorIs(t, vs.UnmarshalText([]byte("z0OIl")), varsig.ErrUnsupportedMultibase)
+	})
+
+	t.Run("fails - trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		data := append(varsig.Ed25519(varsig.PayloadEncodingDAGCBOR).Encode(), 0xff, 0xee)
+		text := "u" + base64.RawURLEncoding.EncodeToString(data)
+		jsonText, err := json.Marshal(text)
+		require.NoError(t, err)
+
+		for _, u := range []interface {
+			encoding.BinaryUnmarshaler
+			encoding.TextUnmarshaler
+			json.Unmarshaler
+		}{&varsig.EdDSAVarsig{}, &varsig.Any{}} {
+			require.ErrorIs(t, u.UnmarshalBinary(data), varsig.ErrTrailingBytes)
+			require.ErrorIs(t, u.UnmarshalText([]byte(text)), varsig.ErrTrailingBytes)
+			require.ErrorIs(t, u.UnmarshalJSON(jsonText), varsig.ErrTrailingBytes)
+		}
+	})
+
+	t.Run("fails - nil Any", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.Any{}.MarshalText()
+		require.ErrorIs(t, err, varsig.ErrNilVarsig)
+	})
+}
@@ -0,0 +1,215 @@
+package varsig
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Multibase is the prefix character identifying the base used to encode
+// a [multibase] string.
+//
+// [multibase]: https://github.com/multiformats/multibase
+type Multibase byte
+
+// Constant values for the bases that can be used to encode a varsig as a
+// multibase string.
+const (
+	MultibaseBase16       = Multibase('f')
+	MultibaseBase16Upper  = Multibase('F')
+	MultibaseBase32       = Multibase('b')
+	MultibaseBase32Upper  = Multibase('B')
+	MultibaseBase58BTC    = Multibase('z')
+	MultibaseBase64       = Multibase('m')
+	MultibaseBase64Pad    = Multibase('M')
+	MultibaseBase64URL    = Multibase('u')
+	MultibaseBase64URLPad = Multibase('U')
+)
+
+const base58BTCAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+	base32Upper = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// EncodeMultibase returns the encoded varsig as a multibase string using
+// the provided base.
+func EncodeMultibase(v Varsig, base Multibase) (string, error) {
+	data, err := encodeVarsig(v)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeMultibase(base, data)
+}
+
+// DecodeMultibase converts the provided multibase string into one of the
+// Varsig types provided by the DefaultRegistry.  The base is determined
+// by the string's prefix.
+func DecodeMultibase(s string, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().DecodeMultibase(s, opts...)
+}
+
+// DecodeMultibase converts the provided multibase string into one of the
+// registered Varsig types, with the behavior changed by the provided
+// options.  The base is determined by the string's prefix, and the
+// decoded bytes must hold exactly one varsig (see ErrTrailingBytes.)
+func (rs Registry) DecodeMultibase(s string, opts ...DecodeOption) (Varsig, error) {
+	data, err := decodeMultibase(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.DecodeStrict(data, opts...)
+}
+
+// DecodeMultibaseRaw returns the bytes encoded in the provided multibase
+// string without decoding them as a varsig, for instance to inspect data
+// that isn't a valid varsig.  The base is determined by the string's
+// prefix.
+func DecodeMultibaseRaw(s string) ([]byte, error) {
+	return decodeMultibase(s)
+}
+
+// EncodeMultibase returns the encoded EdDSAVarsig as a multibase string
+// using the provided base.
+func (v EdDSAVarsig) EncodeMultibase(base Multibase) (string, error) {
+	return EncodeMultibase(v, base)
+}
+
+// EncodeMultibase returns the encoded ECDSAVarsig as a multibase string
+// using the provided base.
+func (v ECDSAVarsig) EncodeMultibase(base Multibase) (string, error) {
+	return EncodeMultibase(v, base)
+}
+
+// EncodeMultibase returns the encoded RSAVarsig as a multibase string
+// using the provided base.
+func (v RSAVarsig) EncodeMultibase(base Multibase) (string, error) {
+	return EncodeMultibase(v, base)
+}
+
+// encodeMultibase encodes the data using the base and prepends the base's
+// prefix.
+func encodeMultibase(base Multibase, data []byte) (string, error) {
+	var text string
+
+	switch base {
+	case MultibaseBase16:
+		text = hex.EncodeToString(data)
+	case MultibaseBase16Upper:
+		text = strings.ToUpper(hex.EncodeToString(data))
+	case MultibaseBase32:
+		text = base32Lower.EncodeToString(data)
+	case MultibaseBase32Upper:
+		text = base32Upper.EncodeToString(data)
+	case MultibaseBase58BTC:
+		text = encodeBase58BTC(data)
+	case MultibaseBase64:
+		text = base64.RawStdEncoding.EncodeToString(data)
+	case MultibaseBase64Pad:
+		text = base64.StdEncoding.EncodeToString(data)
+	case MultibaseBase64URL:
+		text = base64.RawURLEncoding.EncodeToString(data)
+	case MultibaseBase64URLPad:
+		text = base64.URLEncoding.EncodeToString(data)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedMultibase, rune(base))
+	}
+
+	return string(base) + text, nil
+}
+
+// decodeMultibase decodes a multibase string using the base identified
+// by its prefix.
+func decodeMultibase(s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty string", ErrUnsupportedMultibase)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch base, text := Multibase(s[0]), s[1:]; base {
+	case MultibaseBase16, MultibaseBase16Upper:
+		data, err = hex.DecodeString(text)
+	case MultibaseBase32:
+		data, err = base32Lower.DecodeString(text)
+	case MultibaseBase32Upper:
+		data, err = base32Upper.DecodeString(text)
+	case MultibaseBase58BTC:
+		data, err = decodeBase58BTC(text)
+	case MultibaseBase64:
+		data, err = base64.RawStdEncoding.DecodeString(text)
+	case MultibaseBase64Pad:
+		data, err = base64.StdEncoding.DecodeString(text)
+	case MultibaseBase64URL:
+		data, err = base64.RawURLEncoding.DecodeString(text)
+	case MultibaseBase64URLPad:
+		data, err = base64.URLEncoding.DecodeString(text)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMultibase, rune(base))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedMultibase, err)
+	}
+
+	return data, nil
+}
+
+func encodeBase58BTC(data []byte) string {
+	var sb strings.Builder
+
+	// Each leading zero byte is encoded as the first character of the
+	// alphabet.
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+
+		sb.WriteByte(base58BTCAlphabet[0])
+	}
+
+	digits := []byte(new(big.Int).SetBytes(data).Text(58))
+	for i, d := range digits {
+		// big.Int uses 0-9a-zA-V for base 58.
+		digits[i] = base58BTCAlphabet[strings.IndexByte("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUV", d)]
+	}
+
+	if len(digits) == 1 && digits[0] == base58BTCAlphabet[0] {
+		digits = nil
+	}
+
+	sb.Write(digits)
+
+	return sb.String()
+}
+
+func decodeBase58BTC(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58BTCAlphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(58)
+
+	for i := zeros; i < len(s); i++ {
+		d := strings.IndexByte(base58BTCAlphabet, s[i])
+		if d < 0 {
+			return nil, fmt.Errorf("invalid base58btc character %q at %d", s[i], i)
+		}
+
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
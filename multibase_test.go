@@ -0,0 +1,108 @@
+package varsig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestMultibase(t *testing.T) {
+	t.Parallel()
+
+	vs := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+
+	for _, tc := range []struct {
+		name string
+		base varsig.Multibase
+		text string
+	}{
+		{name: "base16", base: varsig.MultibaseBase16, text: "f3401ed01ed011371"},
+		{name: "base16upper", base: varsig.MultibaseBase16Upper, text: "F3401ED01ED011371"},
+		{name: "base32", base: varsig.MultibaseBase32, text: "bgqa62apnaejxc"},
+		{name: "base32upper", base: varsig.MultibaseBase32Upper, text: "BGQA62APNAEJXC"},
+		{name: "base58btc", base: varsig.MultibaseBase58BTC, text: "z9hYDEAqjN5e"},
+		{name: "base64", base: varsig.MultibaseBase64, text: "mNAHtAe0BE3E"},
+		{name: "base64pad", base: varsig.MultibaseBase64Pad, text: "MNAHtAe0BE3E="},
+		{name: "base64url", base: varsig.MultibaseBase64URL, text: "uNAHtAe0BE3E"},
+		{name: "base64urlpad", base: varsig.MultibaseBase64URLPad, text: "UNAHtAe0BE3E="},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, err := vs.EncodeMultibase(tc.base)
+			require.NoError(t, err)
+			require.Equal(t, tc.text, text)
+
+			rt, err := varsig.DecodeMultibase(text)
+			require.NoError(t, err)
+			require.Equal(t, vs, rt)
+		})
+	}
+
+	t.Run("base58btc leading zeros", func(t *testing.T) {
+		t.Parallel()
+
+		// The prefix is checked by the registry, so this also exercises
+		// the base58btc decoding of leading zero bytes.
+		_, err := varsig.DecodeMultibase("z11")
+		require.ErrorIs(t, err, varsig.ErrBadPrefix)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := testRegistry(t).DecodeMultibase("f34018120")
+		require.NoError(t, err)
+		require.Equal(t, testAlgorithm1, vs.Algorithm())
+	})
+
+	t.Run("non-canonical", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.DecodeMultibase("f3401ed01ed01930071")
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		vs, err := varsig.DecodeMultibase("f3401ed01ed01930071", varsig.WithNonCanonical())
+		require.NoError(t, err)
+		require.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), vs)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := varsig.DecodeMultibaseRaw("z11")
+		require.NoError(t, err)
+		require.Equal(t, []byte{0, 0}, data)
+
+		data, err = varsig.DecodeMultibaseRaw("f3401ed01ed011371dead")
+		require.NoError(t, err)
+		require.Equal(t, mustDecodeHex(t, "3401ed01ed011371dead"), data)
+
+		_, err = varsig.DecodeMultibaseRaw("x00")
+		require.ErrorIs(t, err, varsig.ErrUnsupportedMultibase)
+	})
+
+	t.Run("fails - trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.DecodeMultibase("f3401ed01ed011371dead")
+		require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.EncodeMultibase(vs, varsig.Multibase('x'))
+		require.ErrorIs(t, err, varsig.ErrUnsupportedMultibase)
+
+		_, err = varsig.EncodeMultibase(nil, varsig.MultibaseBase64URL)
+		require.ErrorIs(t, err, varsig.ErrNilVarsig)
+
+		for _, text := range []string{"", "NAHtAe0BE3E", "f3401ed01ed01137", "bGQA62APNAEJXC", "mNAHtAe0BE3E=", "z0OIl"} {
+			_, err = varsig.DecodeMultibase(text)
+			require.ErrorIs(t, err, varsig.ErrUnsupportedMultibase, text)
+		}
+	})
+}
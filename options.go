@@ -0,0 +1,221 @@
+package varsig
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeOption changes the behavior of the decoding functions accepting
+// options, such as Registry.DecodeWithOptions for streams and
+// Registry.DecodeBytes for byte slices.
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions are the options applied while decoding a varsig.  They
+// travel with the BytesReader (see decodeReader) so that the decoding
+// functions of each field can access them.
+type decodeOptions struct {
+	ctx               context.Context
+	allowNonCanonical bool
+	limits            Limits
+	payloadEncodings  map[uint64]PayloadEncoding
+	strict            bool
+	webAuthn          bool
+	policy            func(Varsig) error
+}
+
+// WithNonCanonical accepts uvarints that aren't minimally encoded (see
+// AllowNonCanonical.)
+func WithNonCanonical() DecodeOption {
+	return func(o *decodeOptions) {
+		o.allowNonCanonical = true
+	}
+}
+
+// WithLimits enforces the provided limits (see LimitReader.)
+func WithLimits(limits Limits) DecodeOption {
+	return func(o *decodeOptions) {
+		o.limits = limits
+	}
+}
+
+// WithMaxSize limits the number of bytes read to decode the varsig.
+func WithMaxSize(size int) DecodeOption {
+	return func(o *decodeOptions) {
+		o.limits.MaxSize = size
+	}
+}
+
+// WithPayloadEncodings maps single-segment payload encodings to
+// PayloadEncoding values, taking precedence over the built-in payload
+// encodings.  This allows decoding payload encodings that this library
+// encodes but doesn't decode by default (such as PayloadEncodingJWT), or
+// rejecting supported ones with ErrUnsupportedPayloadEncoding by mapping
+// them to PayloadEncodingUnspecified.  Any other value must encode back to
+// the segment it's mapped from, so that the decoded varsig round-trips.
+// Decoding with a table that doesn't follow these rules, or that maps the
+// first segment of a nested payload encoding (EIP-191 and WebAuthn),
+// fails with ErrUnsupportedPayloadEncoding.
+func WithPayloadEncodings(table map[uint64]PayloadEncoding) DecodeOption {
+	return func(o *decodeOptions) {
+		o.payloadEncodings = table
+	}
+}
+
+// WithWebAuthn accepts the WebAuthn payload encodings.  They use a value
+// from the private use range of the multicodec table until one is
+// registered, so they're rejected with ErrUnsupportedPayloadEncoding by
+// default: the same value may mean something else to other applications.
+//
+// The Unmarshal functions and methods (UnmarshalBinary, UnmarshalDAGCBOR,
+// etc.) always accept them, so that whatever this library marshals can be
+// unmarshaled.
+func WithWebAuthn() DecodeOption {
+	return func(o *decodeOptions) {
+		o.webAuthn = true
+	}
+}
+
+// unmarshalOptions returns the options used by the Unmarshal functions
+// and methods, which accept the WebAuthn payload encodings.
+func unmarshalOptions(opts []DecodeOption) []DecodeOption {
+	return append([]DecodeOption{WithWebAuthn()}, opts...)
+}
+
+// WithStrict rejects data continuing after the varsig with
+// ErrTrailingBytes, like DecodeStrict does.  When decoding from a stream,
+// a byte is read past the varsig to check that the stream ended.
+func WithStrict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// WithPolicy checks the decoded varsig with the provided function, for
+// instance to reject weak hash algorithms.  The error returned by policy
+// is wrapped with ErrPolicyViolation.
+func WithPolicy(policy func(Varsig) error) DecodeOption {
+	return func(o *decodeOptions) {
+		o.policy = policy
+	}
+}
+
+// DecodeWithOptions converts data read from the provided io.Reader into
+// one of the Varsig types provided by the DefaultRegistry.  See
+// Registry.DecodeWithOptions.
+func DecodeWithOptions(ctx context.Context, r BytesReader, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().DecodeWithOptions(ctx, r, opts...)
+}
+
+// DecodeWithOptions converts data read from the provided io.Reader into
+// one of the registered Varsig types, with the behavior changed by the
+// provided options.  The options are applied on top of the ones already
+// set by LimitReader or AllowNonCanonical.  Decoding stops with the
+// context's error if it's canceled while reading.
+//
+// There is no option to choose the allowed versions: v1 is the only
+// version this library decodes, so every other version (including v0) is
+// rejected with ErrUnsupportedVersion.
+func (rs Registry) DecodeWithOptions(ctx context.Context, r BytesReader, opts ...DecodeOption) (Varsig, error) {
+	d := newStreamReader(r)
+	for _, opt := range opts {
+		opt(&d.decodeOptions)
+	}
+
+	d.ctx = ctx
+
+	return rs.decode(d)
+}
+
+// newDecodeOptions returns the options set by opts.
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	var o decodeOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// newSliceReader returns a decodeReader reading data in place with the
+// provided options.  The options are only copied when there are some, so
+// that the reader can stay on the stack.
+func newSliceReader(data []byte, opts []DecodeOption) decodeReader {
+	d := decodeReader{data: data}
+	if len(opts) > 0 {
+		d.decodeOptions = newDecodeOptions(opts)
+	}
+
+	return d
+}
+
+// checkTable returns an error if the table set with WithPayloadEncodings
+// maps the first segment of a nested payload encoding, or maps a segment
+// to a PayloadEncoding that doesn't encode back to it.
+func (o *decodeOptions) checkTable() error {
+	for seg1, enc := range o.payloadEncodings {
+		if _, ok := nestedPayloadEncodingFor(seg1); ok {
+			return fmt.Errorf("%w: the nested payload encoding %x can't be mapped", ErrUnsupportedPayloadEncoding, seg1)
+		}
+
+		if enc == PayloadEncodingUnspecified {
+			continue
+		}
+
+		encoded, ok := appendPayloadEncoding(nil, enc)
+		if !ok || !bytes.Equal(encoded, binary.AppendUvarint(nil, seg1)) {
+			return fmt.Errorf("%w: %x can't be mapped to %v, which doesn't encode to it", ErrUnsupportedPayloadEncoding, seg1, enc)
+		}
+	}
+
+	return nil
+}
+
+// checksDecoded returns true if the decoded varsig must be checked with
+// checkDecoded.
+func (o *decodeOptions) checksDecoded() bool {
+	return o.strict || o.policy != nil
+}
+
+// checkDecoded applies the options that check the decoded varsig.  When
+// decoding from a stream, the trailing bytes are read from the source
+// directly, so that they're not subject to the limits.
+func (d *decodeReader) checkDecoded(vs Varsig) error {
+	if d.strict {
+		if err := d.checkEnd(); err != nil {
+			return err
+		}
+	}
+
+	if d.policy != nil {
+		if err := d.policy(vs); err != nil {
+			return fmt.Errorf("%w: %w", ErrPolicyViolation, err)
+		}
+	}
+
+	return nil
+}
+
+// checkEnd returns ErrTrailingBytes unless all the data was read.
+func (d *decodeReader) checkEnd() error {
+	if d.src == nil {
+		return checkTrailingBytes(d.data[d.offset:])
+	}
+
+	var b [1]byte
+
+	n, err := io.ReadFull(d.src, b[:])
+	if n > 0 {
+		return fmt.Errorf("%w: data continues after the varsig", ErrTrailingBytes)
+	}
+
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package varsig_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDecodeWithOptions(t *testing.T) {
+	t.Parallel()
+
+	ed25519 := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+	data := ed25519.Encode()
+
+	decode := func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error) {
+		return varsig.DecodeWithOptions(context.Background(), bytes.NewReader(data), opts...)
+	}
+
+	t.Run("passes - defaults", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := varsig.DefaultRegistry().DecodeWithOptions(context.Background(), bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+
+		_, err = decode(mustDecodeHex(t, "3401ed01ed01930071"))
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+	})
+
+	t.Run("passes - non-canonical", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := decode(mustDecodeHex(t, "3401ed01ed01930071"), varsig.WithNonCanonical())
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := decode(data, varsig.WithStrict(), varsig.WithMaxSize(len(data)))
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+
+		vs, err = decode(append(data, 0x00), varsig.WithStrict())
+		require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+		assert.Nil(t, vs)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := decode(data, varsig.WithMaxSize(len(data)-1))
+		require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+		assert.Nil(t, vs)
+
+		vs, err = decode(data, varsig.WithLimits(varsig.Limits{
+			MaxValues: map[string]uint64{varsig.ParameterHash: uint64(varsig.HashSha2_256)},
+		}))
+		require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+		assert.Nil(t, vs)
+	})
+
+	t.Run("reader options are kept", func(t *testing.T) {
+		t.Parallel()
+
+		rs256 := varsig.RS256(0x100, varsig.PayloadEncodingDAGCBOR).Encode()
+		r := varsig.LimitReader(bytes.NewReader(rs256), varsig.Limits{
+			MaxValues: map[string]uint64{varsig.ParameterKeyLength: 100},
+		})
+
+		vs, err := varsig.DecodeWithOptions(context.Background(), r, varsig.WithStrict())
+		require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+		assert.Nil(t, vs)
+
+		r = varsig.AllowNonCanonical(bytes.NewReader(mustDecodeHex(t, "3401ed01ed01930071")))
+		vs, err = varsig.DecodeWithOptions(context.Background(), r, varsig.WithStrict())
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		t.Parallel()
+
+		errWeakHash := errors.New("weak hash")
+		policy := varsig.WithPolicy(func(vs varsig.Varsig) error {
+			if vs.Hash() == varsig.HashSha1 {
+				return errWeakHash
+			}
+
+			return nil
+		})
+
+		vs, err := decode(data, policy)
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+
+		vs, err = decode(varsig.NewECDSAVarsig(varsig.CurveP256, varsig.HashSha1, varsig.PayloadEncodingVerbatim).Encode(), policy)
+		require.ErrorIs(t, err, varsig.ErrPolicyViolation)
+		require.ErrorIs(t, err, errWeakHash)
+		require.EqualError(t, err, "varsig rejected by policy: weak hash")
+		assert.Nil(t, vs)
+	})
+
+	t.Run("payload encodings", func(t *testing.T) {
+		t.Parallel()
+
+		jwt := varsig.ES256(varsig.PayloadEncodingJWT).Encode()
+		table := varsig.WithPayloadEncodings(map[uint64]varsig.PayloadEncoding{
+			0x6a77: varsig.PayloadEncodingJWT,
+			0x71:   varsig.PayloadEncodingUnspecified,
+		})
+
+		_, err := decode(jwt)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+
+		vs, err := decode(jwt, table)
+		require.NoError(t, err)
+		assert.Equal(t, varsig.ES256(varsig.PayloadEncodingJWT), vs)
+
+		vs, err = decode(data, table)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+		assert.Nil(t, vs)
+	})
+
+	t.Run("payload encodings - nested", func(t *testing.T) {
+		t.Parallel()
+
+		eip191 := varsig.ES256K(varsig.PayloadEncodingEIP191Raw).Encode()
+
+		for _, table := range []map[uint64]varsig.PayloadEncoding{
+			{0xe191: varsig.PayloadEncodingEIP191Raw},
+			{0x300000: varsig.PayloadEncodingUnspecified},
+		} {
+			vs, err := decode(eip191, varsig.WithPayloadEncodings(table), varsig.WithStrict())
+			require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+			assert.Nil(t, vs)
+
+			vs, err = decode(data, varsig.WithPayloadEncodings(table))
+			require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+			assert.Nil(t, vs)
+		}
+	})
+
+	t.Run("payload encodings - not round-tripping", func(t *testing.T) {
+		t.Parallel()
+
+		for _, table := range []map[uint64]varsig.PayloadEncoding{
+			{0x1234: varsig.PayloadEncoding(99)},
+			{0x1234: varsig.PayloadEncodingDAGCBOR},
+			{0x70: varsig.PayloadEncodingEIP191Raw},
+		} {
+			vs, err := decode(mustDecodeHex(t, "3401ed01ed0113b424"), varsig.WithPayloadEncodings(table))
+			require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+			assert.Nil(t, vs)
+
+			vs, err = decode(data, varsig.WithPayloadEncodings(table))
+			require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+			assert.Nil(t, vs)
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		vs, err := varsig.DecodeWithOptions(ctx, bytes.NewReader(data))
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, vs)
+	})
+}
+
+func TestDecodeBytesWithOptions(t *testing.T) {
+	t.Parallel()
+
+	ed25519 := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+	data := ed25519.Encode()
+	nonCanonical := mustDecodeHex(t, "3401ed01ed01930071")
+
+	for _, tc := range []struct {
+		name   string
+		decode func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error)
+	}{
+		{name: "Decode", decode: varsig.Decode},
+		{name: "Registry.Decode", decode: varsig.DefaultRegistry().Decode},
+		{
+			name: "DecodeBytes",
+			decode: func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error) {
+				vs, _, err := varsig.DecodeBytes(data, opts...)
+				return vs, err
+			},
+		},
+		{
+			name: "DecodePrefix",
+			decode: func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error) {
+				vs, _, err := varsig.DecodePrefix(data, opts...)
+				return vs, err
+			},
+		},
+		{
+			name: "EdDSAVarsig.DecodeBytes",
+			decode: func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error) {
+				var v varsig.EdDSAVarsig
+				if _, err := v.DecodeBytes(data, opts...); err != nil {
+					return nil, err
+				}
+
+				return v, nil
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vs, err := tc.decode(nonCanonical)
+			require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+			assert.Nil(t, vs)
+
+			vs, err = tc.decode(nonCanonical, varsig.WithNonCanonical())
+			require.NoError(t, err)
+			assert.Equal(t, ed25519, vs)
+
+			vs, err = tc.decode(append(data, 0x00), varsig.WithStrict())
+			require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+			assert.Nil(t, vs)
+
+			vs, err = tc.decode(data, varsig.WithMaxSize(len(data)-1))
+			require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+			assert.Nil(t, vs)
+
+			vs, err = tc.decode(data, varsig.WithPolicy(func(varsig.Varsig) error { return errors.New("rejected") }))
+			require.ErrorIs(t, err, varsig.ErrPolicyViolation)
+			assert.Nil(t, vs)
+		})
+	}
+
+	t.Run("DecodeStrict", func(t *testing.T) {
+		t.Parallel()
+
+		opts := make([]varsig.DecodeOption, 1, 2)
+		opts[0] = varsig.WithNonCanonical()
+
+		vs, err := varsig.DecodeStrict(nonCanonical, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, ed25519, vs)
+
+		vs, err = varsig.DecodeStrict(append(nonCanonical, 0x00), opts...)
+		require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+		assert.Nil(t, vs)
+
+		// The options of the caller aren't changed.
+		assert.Len(t, opts, 1)
+		assert.Nil(t, opts[:2][1])
+	})
+
+	t.Run("DecodeSeqBytes", func(t *testing.T) {
+		t.Parallel()
+
+		var n int
+
+		for vs, err := range varsig.DecodeSeqBytes(append(nonCanonical, data...), varsig.WithNonCanonical(), varsig.WithStrict()) {
+			require.NoError(t, err)
+			assert.Equal(t, ed25519, vs)
+
+			n++
+		}
+
+		assert.Equal(t, 2, n)
+	})
+}
@@ -0,0 +1,152 @@
+package varsig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// descriptorSeparator separates the fields of a varsig descriptor.
+const descriptorSeparator = ":"
+
+// stringSeparator separates the fields of a varsig formatted by its String
+// method, which Parse also accepts.
+const stringSeparator = "/"
+
+// Parse converts a compact textual descriptor into the Varsig it
+// describes.  Descriptors list the varsig's fields, in the order they are
+// encoded, separated by colons:
+//
+//	eddsa:<curve>:<hash>:<payload-encoding>
+//	ecdsa:<curve>:<hash>:<payload-encoding>
+//	rsa:<hash>:<key-bits>:<payload-encoding>
+//
+// The names used for each field are those returned by the String method of
+// the corresponding type and are matched case-insensitively, so for
+// example "eddsa:ed25519:sha2-512:dag-cbor" describes the same varsig as
+// Ed25519(PayloadEncodingDAGCBOR).  The RSA key length is given in bits
+// (a multiple of 8, such as 2048), in decimal or 0x-prefixed hexadecimal.
+//
+// The output of the String method of the Varsig types provided by this
+// library, such as "EdDSA/Ed25519/sha2-512/dag-cbor v1", is also accepted
+// and parses back to the same varsig.
+func Parse(descriptor string) (Varsig, error) {
+	text := strings.TrimSpace(descriptor)
+
+	sep := descriptorSeparator
+	if strings.Contains(text, stringSeparator) {
+		sep = stringSeparator
+
+		if rest, vers, ok := strings.Cut(text, " "); ok {
+			if !strings.EqualFold(vers, Version1.String()) {
+				return nil, fmt.Errorf("%w %q: %w: %s", ErrBadDescriptor, descriptor, ErrUnsupportedVersion, vers)
+			}
+
+			text = rest
+		}
+	}
+
+	fields := strings.Split(text, sep)
+
+	algo, ok := parseConstant(algorithmNames, fields[0])
+	if !ok {
+		return nil, fmt.Errorf("%w %q: %w: %s", ErrBadDescriptor, descriptor, ErrUnknownAlgorithm, fields[0])
+	}
+
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("%w %q: expected 4 fields for %s, got %d", ErrBadDescriptor, descriptor, algo, len(fields))
+	}
+
+	vs, err := parseDescriptorFields(algo, fields[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrBadDescriptor, descriptor, err)
+	}
+
+	return vs, nil
+}
+
+func parseDescriptorFields(algo Algorithm, fields []string) (Varsig, error) {
+	payEnc, err := ParsePayloadEncoding(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	switch algo {
+	case AlgorithmEdDSA:
+		curve, ok := parseConstant(edDSACurveNames, fields[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEdDSACurve, fields[0])
+		}
+
+		hashAlg, err := ParseHash(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEdDSAVarsig(curve, hashAlg, payEnc), nil
+	case AlgorithmECDSA:
+		curve, ok := parseConstant(ecdsaCurveNames, fields[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownECDSACurve, fields[0])
+		}
+
+		hashAlg, err := ParseHash(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return NewECDSAVarsig(curve, hashAlg, payEnc), nil
+	default: // AlgorithmRSA
+		hashAlg, err := ParseHash(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		keyBits, err := strconv.ParseUint(fields[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key length: %w", err)
+		}
+
+		if keyBits == 0 || keyBits%8 != 0 {
+			return nil, fmt.Errorf("invalid key length: %d bits isn't a positive multiple of 8", keyBits)
+		}
+
+		return NewRSAVarsig(hashAlg, keyBits/8, payEnc), nil
+	}
+}
+
+// ParseHash returns the hash algorithm with the provided (case-insensitive)
+// name, as returned by Hash.String.
+func ParseHash(name string) (Hash, error) {
+	h, ok := parseConstant(hashNames, name)
+	if !ok || h == HashUnspecified {
+		return HashUnspecified, fmt.Errorf("%w: %s", ErrUnknownHash, name)
+	}
+
+	return h, nil
+}
+
+// ParsePayloadEncoding returns the payload encoding with the provided
+// (case-insensitive) name, as returned by PayloadEncoding.String.
+func ParsePayloadEncoding(name string) (PayloadEncoding, error) {
+	enc, ok := parseConstant(payloadEncodingNames, name)
+	if !ok || enc == PayloadEncodingUnspecified {
+		return PayloadEncodingUnspecified, fmt.Errorf("%w: %s", ErrUnsupportedPayloadEncoding, name)
+	}
+
+	return enc, nil
+}
+
+// parseConstant returns the constant whose human-readable name matches
+// the provided text, ignoring case.
+func parseConstant[T ~uint64 | ~int](names map[T]constantName, text string) (T, bool) {
+	text = strings.TrimSpace(text)
+
+	for v, name := range names {
+		if strings.EqualFold(name.text, text) {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package varsig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			descriptor string
+			varsig     varsig.Varsig
+		}{
+			{descriptor: "eddsa:ed25519:sha2-512:dag-cbor", varsig: varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)},
+			{descriptor: "EdDSA:Ed448:shake-256:verbatim", varsig: varsig.Ed448(varsig.PayloadEncodingVerbatim)},
+			{descriptor: "ecdsa:p-256:sha2-256:dag-json", varsig: varsig.ES256(varsig.PayloadEncodingDAGJSON)},
+			{descriptor: "ecdsa:secp256k1:keccak-256:eip191-raw", varsig: must(varsig.EIP191(varsig.PayloadEncodingEIP191Raw))},
+			{descriptor: "rsa:sha2-256:2048:verbatim", varsig: varsig.RS256(256, varsig.PayloadEncodingVerbatim)},
+			{descriptor: " rsa:sha2-512:0x1000:dag-cbor\n", varsig: varsig.RS512(0x200, varsig.PayloadEncodingDAGCBOR)},
+			{descriptor: "EdDSA/Ed25519/sha2-512/dag-cbor v1", varsig: varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)},
+			{descriptor: "RSA/sha2-256/2048/verbatim", varsig: varsig.RS256(256, varsig.PayloadEncodingVerbatim)},
+		} {
+			t.Run(tc.descriptor, func(t *testing.T) {
+				t.Parallel()
+
+				vs, err := varsig.Parse(tc.descriptor)
+				require.NoError(t, err)
+				require.Equal(t, tc.varsig, vs)
+			})
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			descriptor string
+			err        error
+		}{
+			{descriptor: "", err: varsig.ErrUnknownAlgorithm},
+			{descriptor: "dsa:sha1:verbatim", err: varsig.ErrUnknownAlgorithm},
+			{descriptor: "eddsa:ed25519:sha2-512", err: varsig.ErrBadDescriptor},
+			{descriptor: "eddsa:ed25519:sha2-512:dag-cbor:v1", err: varsig.ErrBadDescriptor},
+			{descriptor: "eddsa:p-256:sha2-512:dag-cbor", err: varsig.ErrUnknownEdDSACurve},
+			{descriptor: "ecdsa:ed25519:sha2-256:dag-cbor", err: varsig.ErrUnknownECDSACurve},
+			{descriptor: "ecdsa:p-256:sha2-257:dag-cbor", err: varsig.ErrUnknownHash},
+			{descriptor: "ecdsa:p-256:unspecified:dag-cbor", err: varsig.ErrUnknownHash},
+			{descriptor: "rsa:sha2-256:2048:cbor", err: varsig.ErrUnsupportedPayloadEncoding},
+			{descriptor: "rsa:sha2-256:big:verbatim", err: varsig.ErrBadDescriptor},
+			{descriptor: "rsa:sha2-256:2047:verbatim", err: varsig.ErrBadDescriptor},
+			{descriptor: "rsa:sha2-256:0:verbatim", err: varsig.ErrBadDescriptor},
+			{descriptor: "EdDSA/Ed25519/sha2-512/dag-cbor v2", err: varsig.ErrUnsupportedVersion},
+		} {
+			t.Run(tc.descriptor, func(t *testing.T) {
+				t.Parallel()
+
+				vs, err := varsig.Parse(tc.descriptor)
+				require.ErrorIs(t, err, varsig.ErrBadDescriptor)
+				require.ErrorIs(t, err, tc.err)
+				require.Nil(t, vs)
+			})
+		}
+	})
+
+	t.Run("passes - String round-trip", func(t *testing.T) {
+		t.Parallel()
+
+		for _, vs := range appendVarsigs {
+			t.Run(vs.String(), func(t *testing.T) {
+				t.Parallel()
+
+				rt, err := varsig.Parse(vs.String())
+				require.NoError(t, err)
+				require.Equal(t, vs, rt)
+			})
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package varsig
+
+import (
+	"fmt"
+	"io"
+)
+
+// AllowNonCanonical returns a BytesReader reading from r which, when
+// passed to the decoding functions, accepts uvarints that aren't
+// minimally encoded (such as 0xb4 0x00 for the 0x34 prefix.)  Such
+// encodings are rejected with ErrNonCanonicalVarint by default, since
+// they allow distinct byte strings to decode to the same varsig.  This
+// option is only provided for compatibility with previously stored
+// varsigs; the WithNonCanonical option does the same for the other
+// decoding functions.
+func AllowNonCanonical(r BytesReader) BytesReader {
+	d := newStreamReader(r)
+	WithNonCanonical()(&d.decodeOptions)
+
+	return d
+}
+
+// Limits bounds the resources used to decode varsigs from untrusted
+// data, either streams (see LimitReader) or byte slices (see WithLimits.)
+// The zero value doesn't limit anything.
+type Limits struct {
+	// MaxSize is the maximum number of bytes read to decode a varsig.
+	// Reading past it (including from a custom DecodeFunc) fails with
+	// ErrLimitExceeded.  Zero means no limit.
+	MaxSize int
+
+	// MaxValues maps field names (such as ParameterKeyLength) to their
+	// maximum value.  Fields read by the built-in decoders and by
+	// ReadUvarint are checked against it.
+	MaxValues map[string]uint64
+}
+
+// LimitReader returns a BytesReader reading from r which, when passed to
+// the decoding functions, enforces the provided limits.  For instance,
+// the following limits varsigs to 32 bytes and RSA keys to 8192 bits:
+//
+//	r = varsig.LimitReader(r, varsig.Limits{
+//		MaxSize:   32,
+//		MaxValues: map[string]uint64{varsig.ParameterKeyLength: 1024},
+//	})
+func LimitReader(r BytesReader, limits Limits) BytesReader {
+	d := newStreamReader(r)
+	WithLimits(limits)(&d.decodeOptions)
+
+	return d
+}
+
+// decodeReader reads the fields of a varsig, either in place from a byte
+// slice or from a BytesReader, while applying the decoding options.  All
+// decoding, from slices or streams, goes through it.  It also counts the
+// bytes read to report the offsets of DecodeErrors.
+type decodeReader struct {
+	// src is the stream being read, or nil when reading from data.
+	src  BytesReader
+	data []byte
+
+	decodeOptions
+
+	offset int
+}
+
+// newStreamReader returns a decodeReader reading from r, with r's options
+// if it's already a decodeReader, whose offset starts at zero.
+func newStreamReader(r BytesReader) *decodeReader {
+	d, ok := r.(*decodeReader)
+	if !ok {
+		return &decodeReader{src: r}
+	}
+
+	src := d.src
+	if src == nil {
+		src = d
+	}
+
+	return &decodeReader{src: src, decodeOptions: d.decodeOptions}
+}
+
+// readerOf returns r if it's a decodeReader (so that the decoding
+// functions of each field share its options and offset), or a new
+// decodeReader reading from r.
+func readerOf(r BytesReader) *decodeReader {
+	if d, ok := r.(*decodeReader); ok {
+		return d
+	}
+
+	return &decodeReader{src: r}
+}
+
+// ReadByte reads the next byte.
+func (d *decodeReader) ReadByte() (byte, error) {
+	if err := d.contextErr(); err != nil {
+		return 0, err
+	}
+
+	if d.remaining() == 0 {
+		return 0, d.errMaxSize()
+	}
+
+	if d.src == nil {
+		if d.offset >= len(d.data) {
+			return 0, io.EOF
+		}
+
+		d.offset++
+
+		return d.data[d.offset-1], nil
+	}
+
+	b, err := d.src.ReadByte()
+	if err == nil {
+		d.offset++
+	}
+
+	return b, err
+}
+
+// Read reads up to len(p) bytes.
+func (d *decodeReader) Read(p []byte) (int, error) {
+	if err := d.contextErr(); err != nil {
+		return 0, err
+	}
+
+	switch remaining := d.remaining(); {
+	case remaining == 0 && len(p) > 0:
+		return 0, d.errMaxSize()
+	case remaining >= 0 && len(p) > remaining:
+		p = p[:remaining]
+	}
+
+	if d.src == nil {
+		if d.offset >= len(d.data) && len(p) > 0 {
+			return 0, io.EOF
+		}
+
+		n := copy(p, d.data[d.offset:])
+		d.offset += n
+
+		return n, nil
+	}
+
+	n, err := d.src.Read(p)
+	d.offset += n
+
+	return n, err
+}
+
+// contextErr returns the error of the context passed to
+// DecodeWithOptions, if any.
+func (d *decodeReader) contextErr() error {
+	if d.ctx == nil {
+		return nil
+	}
+
+	return d.ctx.Err()
+}
+
+// remaining returns the number of bytes that can still be read, or -1 if
+// there's no limit.
+func (d *decodeReader) remaining() int {
+	if d.limits.MaxSize <= 0 {
+		return -1
+	}
+
+	return max(d.limits.MaxSize-d.offset, 0)
+}
+
+func (d *decodeReader) errMaxSize() error {
+	return fmt.Errorf("%w: varsig is longer than %d bytes", ErrLimitExceeded, d.limits.MaxSize)
+}
+
+// checkLimit returns a DecodeError if the value of the field exceeds the
+// maximum set with LimitReader.
+func (d *decodeReader) checkLimit(field string, off int, value uint64) error {
+	if limit, ok := d.limits.MaxValues[field]; ok && value > limit {
+		return decodeError(field, off, value, fmt.Errorf("%w: %d is greater than %d", ErrLimitExceeded, value, limit))
+	}
+
+	return nil
+}
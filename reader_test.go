@@ -0,0 +1,137 @@
+package varsig_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestLimitReader(t *testing.T) {
+	t.Parallel()
+
+	rs4096 := varsig.RS256(512, varsig.PayloadEncodingDAGCBOR).Encode()
+
+	t.Run("passes - within limits", func(t *testing.T) {
+		t.Parallel()
+
+		r := varsig.LimitReader(bytes.NewReader(append(rs4096, rs4096...)), varsig.Limits{
+			MaxSize:   len(rs4096),
+			MaxValues: map[string]uint64{varsig.ParameterKeyLength: 512},
+		})
+
+		// The maximum size applies to each varsig.
+		for range 2 {
+			vs, err := varsig.DecodeStream(r)
+			require.NoError(t, err)
+			assert.Equal(t, varsig.RS256(512, varsig.PayloadEncodingDAGCBOR), vs)
+		}
+	})
+
+	t.Run("fails - maximum size", func(t *testing.T) {
+		t.Parallel()
+
+		r := varsig.LimitReader(bytes.NewReader(rs4096), varsig.Limits{MaxSize: len(rs4096) - 1})
+
+		vs, err := varsig.DecodeStream(r)
+		require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+		require.EqualError(t, err, "payload-encoding at offset 7: unsupported payload encoding: decoding limit exceeded: varsig is longer than 7 bytes")
+		assert.Nil(t, vs)
+	})
+
+	t.Run("fails - maximum value", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name   string
+			field  string
+			limit  uint64
+			offset int
+			value  uint64
+		}{
+			{name: "key length", field: varsig.ParameterKeyLength, limit: 256, offset: 5, value: 512},
+			{name: "algorithm", field: varsig.ParameterAlgorithm, limit: 0xff, offset: 2, value: uint64(varsig.AlgorithmRSA)},
+			{name: "hash", field: varsig.ParameterHash, limit: 0x11, offset: 4, value: uint64(varsig.HashSha2_256)},
+			{name: "payload encoding", field: varsig.ParameterPayloadEncoding, limit: 0x70, offset: 7, value: 0x71},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				limits := varsig.Limits{MaxValues: map[string]uint64{tc.field: tc.limit}}
+
+				vs, err := varsig.DecodeStream(varsig.LimitReader(bytes.NewReader(rs4096), limits))
+				assert.Nil(t, vs)
+				checkLimitError(t, err, tc.field, tc.offset, tc.value)
+
+				// The limits apply to byte slices as well.
+				vs, err = varsig.Decode(rs4096, varsig.WithLimits(limits))
+				assert.Nil(t, vs)
+				checkLimitError(t, err, tc.field, tc.offset, tc.value)
+
+				var rsa varsig.RSAVarsig
+				_, err = rsa.DecodeBytes(rs4096, varsig.WithLimits(limits))
+				checkLimitError(t, err, tc.field, tc.offset, tc.value)
+			})
+		}
+	})
+
+	t.Run("fails - custom DecodeFunc", func(t *testing.T) {
+		t.Parallel()
+
+		reg := varsig.NewRegistry()
+		reg.Register(testAlgorithm0, func(r varsig.BytesReader) (varsig.Varsig, error) {
+			if _, err := varsig.ReadUvarint(r, "custom"); err != nil {
+				return nil, err
+			}
+
+			// A DecodeFunc reading until EOF is stopped by the limit.
+			_, err := io.ReadAll(r)
+
+			return nil, err
+		})
+
+		data := append([]byte{0x34, 0x01, 0x80, 0x20, 0x80, 0x01}, make([]byte, 100)...)
+
+		_, err := reg.DecodeStream(varsig.LimitReader(bytes.NewReader(data), varsig.Limits{
+			MaxValues: map[string]uint64{"custom": 0x7f},
+		}))
+		require.EqualError(t, err, "custom at offset 4: decoding limit exceeded: 128 is greater than 127")
+
+		_, err = reg.DecodeStream(varsig.LimitReader(bytes.NewReader(data), varsig.Limits{MaxSize: 64}))
+		require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+	})
+
+	t.Run("passes - combined with AllowNonCanonical", func(t *testing.T) {
+		t.Parallel()
+
+		data := mustDecodeHex(t, "3401ed01ed01938000"+"71")
+		limits := varsig.Limits{MaxSize: len(data)}
+
+		for _, r := range []varsig.BytesReader{
+			varsig.LimitReader(varsig.AllowNonCanonical(bytes.NewReader(data)), limits),
+			varsig.AllowNonCanonical(varsig.LimitReader(bytes.NewReader(data), limits)),
+		} {
+			vs, err := varsig.DecodeStream(r)
+			require.NoError(t, err)
+			assert.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), vs)
+		}
+	})
+}
+
+// checkLimitError checks that err is the ErrLimitExceeded DecodeError of
+// the field at offset.
+func checkLimitError(t *testing.T, err error, field string, offset int, value uint64) {
+	t.Helper()
+
+	require.ErrorIs(t, err, varsig.ErrLimitExceeded)
+
+	var decErr *varsig.DecodeError
+	require.ErrorAs(t, err, &decErr)
+	assert.Equal(t, field, decErr.Field)
+	assert.Equal(t, offset, decErr.Offset)
+	assert.Equal(t, value, decErr.Value)
+}
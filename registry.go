@@ -1,10 +1,6 @@
 package varsig
 
-import (
-	"bytes"
-	"encoding/binary"
-	"fmt"
-)
+import "fmt"
 
 // Version represents which version of the varsig specification was used
 // to produce Varsig value.
@@ -16,6 +12,21 @@ const (
 	Version1 Version = 1
 )
 
+// String returns the version formatted as "v0" or "v1".
+func (v Version) String() string {
+	return fmt.Sprintf("v%d", uint64(v))
+}
+
+// GoString returns the Go syntax representation of the version.
+func (v Version) GoString() string {
+	switch v {
+	case Version0, Version1:
+		return fmt.Sprintf("varsig.Version%d", uint64(v))
+	default:
+		return fmt.Sprintf("varsig.Version(%d)", uint64(v))
+	}
+}
+
 // DecodeFunc is a function that parses the varsig representing a specific
 // signing algorithm.
 type DecodeFunc func(BytesReader) (Varsig, error)
@@ -46,55 +57,126 @@ func (rs Registry) Register(alg Algorithm, decodeFunc DecodeFunc) {
 }
 
 // Decode converts the provided data into one of the registered Varsig
-// types.
-func (rs Registry) Decode(data []byte) (Varsig, error) {
-	return rs.DecodeStream(bytes.NewReader(data))
+// types, with the behavior changed by the provided options.  Any data
+// following the varsig is ignored; use DecodeStrict to reject it or
+// DecodePrefix to retrieve it.
+func (rs Registry) Decode(data []byte, opts ...DecodeOption) (Varsig, error) {
+	vs, _, err := rs.DecodeBytes(data, opts...)
+
+	return vs, err
+}
+
+// DecodeStrict converts the provided data into one of the registered
+// Varsig types, and returns ErrTrailingBytes if the data continues after
+// the varsig.  It's equivalent to Decode with the WithStrict option.
+func (rs Registry) DecodeStrict(data []byte, opts ...DecodeOption) (Varsig, error) {
+	return rs.Decode(data, append(opts[:len(opts):len(opts)], WithStrict())...)
+}
+
+// DecodePrefix converts the varsig at the start of the provided data into
+// one of the registered Varsig types, and returns the data following it
+// (such as the signature when the varsig is concatenated with it.)
+func (rs Registry) DecodePrefix(data []byte, opts ...DecodeOption) (Varsig, []byte, error) {
+	vs, n, err := rs.DecodeBytes(data, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vs, data[n:], nil
 }
 
 // DecodeStream converts data read from the provided io.Reader into one
-// of the registered Varsig types.
+// of the registered Varsig types.  It's equivalent to DecodeWithOptions
+// without any option.
 func (rs Registry) DecodeStream(r BytesReader) (Varsig, error) {
-	pre, err := binary.ReadUvarint(r)
+	return rs.decode(newStreamReader(r))
+}
+
+func (rs Registry) decode(d *decodeReader) (Varsig, error) {
+	algo, algoOff, err := d.header()
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrBadPrefix, err)
+		return nil, err
 	}
 
-	if pre != Prefix {
-		return nil, fmt.Errorf("%w: expected %d, got %d", ErrBadPrefix, Prefix, pre)
+	decodeFunc, ok := rs[algo]
+	if !ok {
+		return nil, unknownAlgorithm(algo, algoOff)
 	}
 
-	vers, algo, err := rs.decodeVersAndAlgo(r)
+	vs, err := decodeFunc(d)
 	if err != nil {
 		return nil, err
 	}
 
-	if vers != Version1 {
-		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, vers)
-	}
-
-	decodeFunc, ok := rs[Algorithm(algo)]
-	if !ok {
-		return nil, fmt.Errorf("%w: %x", ErrUnknownAlgorithm, algo)
+	if err := d.checkDecoded(vs); err != nil {
+		return nil, err
 	}
 
-	return decodeFunc(r)
+	return vs, nil
 }
 
-func (rs Registry) decodeVersAndAlgo(r BytesReader) (Version, Algorithm, error) {
-	vers, err := binary.ReadUvarint(r)
+// header reads the prefix, version and algorithm fields, and returns the
+// algorithm along with the offset of its field.
+func (d *decodeReader) header() (Algorithm, int, error) {
+	if err := d.checkTable(); err != nil {
+		return 0, 0, err
+	}
+
+	pre, err := d.readUvarint()
 	if err != nil {
-		return Version(vers), 0, err
+		return 0, 0, decodeError(ParameterPrefix, 0, 0, fmt.Errorf("%w: %w", ErrBadPrefix, err))
+	}
+
+	if err := d.checkLimit(ParameterPrefix, 0, pre); err != nil {
+		return 0, 0, err
 	}
 
-	if vers > 1 && vers < 64 {
-		return Version(vers), 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion, vers)
+	if pre != Prefix {
+		return 0, 0, decodeError(ParameterPrefix, 0, pre, fmt.Errorf("%w: expected %d, got %d", ErrBadPrefix, Prefix, pre))
 	}
 
+	versOff := d.offset
+
+	vers, err := d.uvarint(ParameterVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// A v0 header has no version field, so the algorithm directly follows
+	// the prefix.
 	if vers >= 64 {
-		return 0, Algorithm(vers), nil
+		return 0, 0, decodeError(ParameterVersion, versOff, uint64(Version0), fmt.Errorf("%w: %d", ErrUnsupportedVersion, Version0))
+	}
+
+	if vers > 1 {
+		return 0, 0, decodeError(ParameterVersion, versOff, vers, fmt.Errorf("%w: %d", ErrUnsupportedVersion, vers))
 	}
 
-	algo, err := binary.ReadUvarint(r)
+	algoOff := d.offset
+
+	algo, err := d.uvarint(ParameterAlgorithm)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if Version(vers) != Version1 {
+		return 0, 0, decodeError(ParameterVersion, versOff, vers, fmt.Errorf("%w: %d", ErrUnsupportedVersion, vers))
+	}
+
+	return Algorithm(algo), algoOff, nil
+}
+
+// unknownAlgorithm returns the error reported when no decoding function
+// is registered for the algorithm.
+func unknownAlgorithm(algo Algorithm, off int) error {
+	return decodeError(ParameterAlgorithm, off, uint64(algo), fmt.Errorf("%w: %v", ErrUnknownAlgorithm, algo))
+}
+
+// checkTrailingBytes returns ErrTrailingBytes if rest isn't empty.
+func checkTrailingBytes(rest []byte) error {
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d bytes", ErrTrailingBytes, len(rest))
+	}
 
-	return Version(vers), Algorithm(algo), err
+	return nil
 }
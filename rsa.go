@@ -2,12 +2,17 @@ package varsig
 
 import (
 	"encoding/binary"
+	"fmt"
+	"math/big"
 )
 
 // AlgorithmRSA is the value specifying an RSA signature.
 const AlgorithmRSA = Algorithm(0x1205)
 
-var _ Varsig = RSAVarsig{}
+var (
+	_ Varsig   = RSAVarsig{}
+	_ Appender = RSAVarsig{}
+)
 
 // RSAVarsig is a varsig that encodes the parameters required to describe
 // an RSA signature.
@@ -32,13 +37,40 @@ func NewRSAVarsig(hashAlgorithm Hash, keyLen uint64, payloadEncoding PayloadEnco
 
 // Encode returns the encoded byte format of the RSAVarsig.
 func (v RSAVarsig) Encode() []byte {
-	buf := v.encode()
+	return v.AppendEncode(make([]byte, 0, v.EncodedLen()))
+}
+
+// AppendEncode appends the encoded byte format of the RSAVarsig to dst and
+// returns the extended buffer.  No allocation occurs when dst has enough
+// capacity.
+func (v RSAVarsig) AppendEncode(dst []byte) []byte {
+	dst = v.appendHeader(dst)
+	dst = binary.AppendUvarint(dst, uint64(v.hashAlg))
+	dst = binary.AppendUvarint(dst, v.keyLen)
+
+	return mustAppendPayloadEncoding(dst, v.payEnc)
+}
+
+// EncodedLen returns the length of the encoded byte format of the
+// RSAVarsig.
+func (v RSAVarsig) EncodedLen() int {
+	return v.headerLen() + uvarintLen(uint64(v.hashAlg)) + uvarintLen(v.keyLen) + payloadEncodingLen(v.payEnc)
+}
 
-	buf = binary.AppendUvarint(buf, uint64(v.hashAlg))
-	buf = binary.AppendUvarint(buf, v.keyLen)
-	buf = append(buf, EncodePayloadEncoding(v.payEnc)...)
+// String returns a human-readable description of the RSAVarsig, formatted
+// as "algorithm/hash/key-bits/payload-encoding version" (the format
+// accepted by Parse.)
+func (v RSAVarsig) String() string {
+	// The key length is encoded in bytes, and may not fit in a uint64
+	// once converted to bits.
+	keyBits := new(big.Int).Lsh(new(big.Int).SetUint64(v.keyLen), 3)
 
-	return buf
+	return fmt.Sprintf("%s/%s/%s/%s %s", v.algo, v.hashAlg, keyBits, v.payEnc, v.Version())
+}
+
+// GoString returns the Go syntax representation of the RSAVarsig.
+func (v RSAVarsig) GoString() string {
+	return fmt.Sprintf("varsig.NewRSAVarsig(%#v, %d, %#v)", v.hashAlg, v.keyLen, v.payEnc)
 }
 
 // Hash returns the value describing the hash algorithm used to hash
@@ -54,20 +86,59 @@ func (v RSAVarsig) KeyLength() uint64 {
 }
 
 func decodeRSA(r BytesReader) (Varsig, error) {
-	hashAlg, err := DecodeHashAlgorithm(r)
-	if err != nil {
+	var v RSAVarsig
+	if err := v.decodeFields(readerOf(r)); err != nil {
 		return nil, err
 	}
 
-	keyLen, err := binary.ReadUvarint(r)
+	return v, nil
+}
+
+// DecodeBytes decodes the RSA varsig at the start of data into v and
+// returns the number of bytes consumed, with the behavior changed by the
+// provided options.  The data is parsed in place and, unless an error is
+// returned or options are provided, without allocating.
+func (v *RSAVarsig) DecodeBytes(data []byte, opts ...DecodeOption) (int, error) {
+	d := newSliceReader(data, opts)
+
+	if err := d.expectAlgorithm(AlgorithmRSA); err != nil {
+		return 0, err
+	}
+
+	var dec RSAVarsig
+	if err := dec.decodeFields(&d); err != nil {
+		return 0, err
+	}
+
+	if d.checksDecoded() {
+		if err := d.checkDecoded(dec); err != nil {
+			return 0, err
+		}
+	}
+
+	*v = dec
+
+	return d.offset, nil
+}
+
+// decodeFields decodes the fields following the algorithm into v.
+func (v *RSAVarsig) decodeFields(d *decodeReader) error {
+	hashAlg, err := d.hash()
+	if err != nil {
+		return err
+	}
+
+	keyLen, err := d.uvarint(ParameterKeyLength)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	payEnc, err := DecodePayloadEncoding(r)
+	payEnc, err := d.payloadEncoding()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return NewRSAVarsig(hashAlg, keyLen, payEnc), nil
+	*v = NewRSAVarsig(hashAlg, keyLen, payEnc)
+
+	return nil
 }
@@ -0,0 +1,140 @@
+package varsig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// SeqError is yielded by the sequence decoding functions when a varsig of
+// the sequence can't be decoded.  It wraps the decoding error (usually a
+// DecodeError, whose offset is relative to the start of the entry.)
+type SeqError struct {
+	// Index is the position of the malformed varsig in the sequence.
+	Index int
+
+	// Offset is the position of the malformed varsig's first byte.
+	Offset int
+
+	// Err is the decoding error.
+	Err error
+}
+
+// Error returns the decoding error's message prefixed by the position of
+// the malformed varsig.
+func (e *SeqError) Error() string {
+	return fmt.Sprintf("varsig %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+// Unwrap returns the decoding error.
+func (e *SeqError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeSeq returns an iterator over the varsigs read back-to-back from r
+// using the DefaultRegistry.  See Registry.DecodeSeq.
+func DecodeSeq(r BytesReader) iter.Seq2[Varsig, error] {
+	return DefaultRegistry().DecodeSeq(r)
+}
+
+// DecodeSeqBytes returns an iterator over the varsigs concatenated in
+// data using the DefaultRegistry.  See Registry.DecodeSeqBytes.
+func DecodeSeqBytes(data []byte, opts ...DecodeOption) iter.Seq2[Varsig, error] {
+	return DefaultRegistry().DecodeSeqBytes(data, opts...)
+}
+
+// DecodeSeq returns an iterator over the varsigs read back-to-back from r
+// into one of the registered Varsig types.  The iteration stops when r
+// reaches EOF between two varsigs.  If a varsig can't be decoded, a
+// SeqError is yielded along with a nil Varsig and the iteration stops,
+// since the start of the next varsig can't be found.
+//
+// The options of readers returned by AllowNonCanonical and LimitReader
+// apply to each varsig of the sequence.
+func (rs Registry) DecodeSeq(r BytesReader) iter.Seq2[Varsig, error] {
+	return func(yield func(Varsig, error) bool) {
+		// The counter is placed under the decoding options so that it
+		// sees every byte read, across all the varsigs.
+		src := newStreamReader(r)
+		counter := &countingReader{BytesReader: src.src}
+		src.src = counter
+
+		// The next varsig follows, so there's nothing to check.
+		src.strict = false
+
+		for i := 0; ; i++ {
+			off := counter.n
+
+			vs, err := rs.DecodeStream(src)
+			if err != nil {
+				if counter.n == off && errors.Is(err, io.EOF) {
+					return
+				}
+
+				yield(nil, &SeqError{Index: i, Offset: off, Err: err})
+
+				return
+			}
+
+			if !yield(vs, nil) {
+				return
+			}
+		}
+	}
+}
+
+// DecodeSeqBytes returns an iterator over the varsigs concatenated in
+// data, decoded into one of the registered Varsig types with the behavior
+// changed by the provided options.  It behaves like DecodeSeq, stopping at
+// the end of data.  The WithStrict option is ignored, since the next
+// varsig follows.
+func (rs Registry) DecodeSeqBytes(data []byte, opts ...DecodeOption) iter.Seq2[Varsig, error] {
+	o := newDecodeOptions(opts)
+	o.strict = false
+
+	return func(yield func(Varsig, error) bool) {
+		for i, off := 0, 0; off < len(data); i++ {
+			d := decodeReader{data: data[off:], decodeOptions: o}
+
+			vs, err := rs.decode(&d)
+			n := d.offset
+			if err != nil {
+				yield(nil, &SeqError{Index: i, Offset: off, Err: err})
+
+				return
+			}
+
+			if !yield(vs, nil) {
+				return
+			}
+
+			off += n
+		}
+	}
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	BytesReader
+
+	n int
+}
+
+// ReadByte reads a byte from the underlying reader.
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.BytesReader.ReadByte()
+	if err == nil {
+		c.n++
+	}
+
+	return b, err
+}
+
+// Read reads from the underlying reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.BytesReader.Read(p)
+	c.n += n
+
+	return n, err
+}
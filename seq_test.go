@@ -0,0 +1,137 @@
+package varsig_test
+
+import (
+	"bytes"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestDecodeSeq(t *testing.T) {
+	t.Parallel()
+
+	seq := []varsig.Varsig{
+		varsig.Ed25519(varsig.PayloadEncodingDAGCBOR),
+		varsig.RS256(256, varsig.PayloadEncodingEIP191Cbor),
+		varsig.EIP712(),
+	}
+
+	var data []byte
+	for _, vs := range seq {
+		data = append(data, vs.Encode()...)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		decode func([]byte, ...varsig.DecodeOption) iter.Seq2[varsig.Varsig, error]
+	}{
+		{name: "DecodeSeq", decode: func(data []byte, _ ...varsig.DecodeOption) iter.Seq2[varsig.Varsig, error] {
+			return varsig.DecodeSeq(bytes.NewReader(data))
+		}},
+		{name: "DecodeSeqBytes", decode: varsig.DecodeSeqBytes},
+		{name: "Registry.DecodeSeq", decode: func(data []byte, _ ...varsig.DecodeOption) iter.Seq2[varsig.Varsig, error] {
+			return varsig.DefaultRegistry().DecodeSeq(bytes.NewReader(data))
+		}},
+		{name: "Registry.DecodeSeqBytes", decode: varsig.DefaultRegistry().DecodeSeqBytes},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("passes", func(t *testing.T) {
+				t.Parallel()
+
+				var decoded []varsig.Varsig
+				for vs, err := range tc.decode(data) {
+					require.NoError(t, err)
+					decoded = append(decoded, vs)
+				}
+				assert.Equal(t, seq, decoded)
+			})
+
+			t.Run("passes - empty", func(t *testing.T) {
+				t.Parallel()
+
+				for range tc.decode(nil) {
+					t.Fatal("unexpected varsig")
+				}
+			})
+
+			t.Run("passes - break", func(t *testing.T) {
+				t.Parallel()
+
+				count := 0
+				for range tc.decode(data) {
+					count++
+					break
+				}
+				assert.Equal(t, 1, count)
+			})
+
+			t.Run("fails - malformed entry", func(t *testing.T) {
+				t.Parallel()
+
+				bad := append(append([]byte{}, data...), 0x34, 0x01, 0xed, 0x01, 0x42)
+				bad = append(bad, data...)
+
+				var (
+					decoded []varsig.Varsig
+					errs    []error
+				)
+				for vs, err := range tc.decode(bad) {
+					if err != nil {
+						assert.Nil(t, vs)
+						errs = append(errs, err)
+
+						continue
+					}
+					decoded = append(decoded, vs)
+				}
+				assert.Equal(t, seq, decoded)
+				require.Len(t, errs, 1)
+				require.ErrorIs(t, errs[0], varsig.ErrUnknownEdDSACurve)
+				require.EqualError(t, errs[0], "varsig 3 at offset 29: curve at offset 4: unknown Edwards curve: 42")
+
+				var seqErr *varsig.SeqError
+				require.ErrorAs(t, errs[0], &seqErr)
+				assert.Equal(t, 3, seqErr.Index)
+				assert.Equal(t, len(data), seqErr.Offset)
+			})
+
+			t.Run("fails - truncated entry", func(t *testing.T) {
+				t.Parallel()
+
+				var errs []error
+				for _, err := range tc.decode(data[:len(data)-1]) {
+					if err != nil {
+						errs = append(errs, err)
+					}
+				}
+				require.Len(t, errs, 1)
+				require.ErrorIs(t, errs[0], varsig.ErrUnsupportedPayloadEncoding)
+
+				var seqErr *varsig.SeqError
+				require.ErrorAs(t, errs[0], &seqErr)
+				assert.Equal(t, 2, seqErr.Index)
+			})
+		})
+	}
+
+	t.Run("passes - reader options", func(t *testing.T) {
+		t.Parallel()
+
+		nonCanonical := mustDecodeHex(t, "3401ed01ed019300"+"71"+"348100ed01ed011371")
+		r := varsig.AllowNonCanonical(bytes.NewReader(nonCanonical))
+
+		count := 0
+		for vs, err := range varsig.DecodeSeq(r) {
+			require.NoError(t, err)
+			assert.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), vs)
+			count++
+		}
+		assert.Equal(t, 2, count)
+	})
+}
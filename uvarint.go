@@ -0,0 +1,76 @@
+package varsig
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// errOverflow is returned when an uvarint doesn't fit in 64 bits, with the
+// same message as binary.ReadUvarint.
+var errOverflow = errors.New("binary: varint overflows a 64-bit integer")
+
+// ReadUvarint reads the uvarint value of the named field.  It's intended
+// for custom DecodeFuncs, so that their fields are decoded like the ones
+// of the built-in Varsig types: non-canonical encodings are rejected
+// (unless r was returned by AllowNonCanonical or the WithNonCanonical
+// option is set), the limits set with LimitReader or WithLimits are
+// enforced, and errors are returned as DecodeErrors.  As with
+// DecodeHashAlgorithm, their offsets are counted from the start of the
+// varsig only when r is the reader passed to a DecodeFunc.
+func ReadUvarint(r BytesReader, field string) (uint64, error) {
+	return readerOf(r).uvarint(field)
+}
+
+// uvarint reads the uvarint value of the named field, and returns
+// DecodeErrors.
+func (d *decodeReader) uvarint(field string) (uint64, error) {
+	off := d.offset
+
+	u, err := d.readUvarint()
+	if err != nil {
+		return 0, decodeError(field, off, 0, err)
+	}
+
+	if err := d.checkLimit(field, off, u); err != nil {
+		return 0, err
+	}
+
+	return u, nil
+}
+
+// readUvarint reads an uvarint like binary.ReadUvarint does, but also
+// returns ErrNonCanonicalVarint if it isn't minimally encoded, unless
+// non-canonical uvarints are allowed.
+func (d *decodeReader) readUvarint() (uint64, error) {
+	var x uint64
+	var s uint
+
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		b, err := d.ReadByte()
+		if err != nil {
+			if i > 0 && errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return x, err
+		}
+
+		if b < 0x80 {
+			if i == binary.MaxVarintLen64-1 && b > 1 {
+				return x, errOverflow
+			}
+
+			if i > 0 && b == 0 && !d.allowNonCanonical {
+				return x, ErrNonCanonicalVarint
+			}
+
+			return x | uint64(b)<<s, nil
+		}
+
+		x |= uint64(b&0x7f) << s
+		s += 7
+	}
+
+	return x, errOverflow
+}
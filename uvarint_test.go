@@ -0,0 +1,102 @@
+package varsig_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestNonCanonicalVarint(t *testing.T) {
+	t.Parallel()
+
+	ed25519 := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR)
+
+	for _, tc := range []struct {
+		name     string
+		hex      string
+		expected varsig.Varsig
+		sentinel error
+	}{
+		{name: "prefix", hex: "b400" + "01ed01ed011371", expected: ed25519, sentinel: varsig.ErrBadPrefix},
+		{name: "version", hex: "34" + "8100" + "ed01ed011371", expected: ed25519},
+		{name: "algorithm", hex: "3401" + "ed8100" + "ed011371", expected: ed25519},
+		{name: "curve", hex: "3401ed01" + "ed818000" + "1371", expected: ed25519},
+		{name: "hash", hex: "3401ed01ed01" + "9300" + "71", expected: ed25519, sentinel: varsig.ErrUnknownHash},
+		{name: "payload encoding", hex: "3401ed01ed0113" + "f100", expected: ed25519, sentinel: varsig.ErrUnsupportedPayloadEncoding},
+		{
+			name:     "nested payload encoding",
+			hex:      "3401ec01e70112" + "91c303" + "df00",
+			expected: varsig.NewECDSAVarsig(varsig.CurveSecp256k1, varsig.HashSha2_256, varsig.PayloadEncodingEIP191Raw),
+			sentinel: varsig.ErrUnsupportedPayloadEncoding,
+		},
+		{name: "RSA key length", hex: "3401852412" + "808280" + "00" + "71", expected: varsig.RS256(256, varsig.PayloadEncodingDAGCBOR)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := mustDecodeHex(t, tc.hex)
+
+			vs, err := varsig.Decode(data)
+			require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+			if tc.sentinel != nil {
+				require.ErrorIs(t, err, tc.sentinel)
+			}
+			assert.Nil(t, vs)
+
+			vs, n, err := varsig.DecodeBytes(data)
+			require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+			assert.Nil(t, vs)
+			assert.Zero(t, n)
+
+			vs, err = varsig.DecodeStream(varsig.AllowNonCanonical(bytes.NewReader(data)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vs)
+		})
+	}
+
+	t.Run("DecodeHashAlgorithm", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.DecodeHashAlgorithm(bytes.NewReader([]byte{0x92, 0x00}))
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		h, err := varsig.DecodeHashAlgorithm(varsig.AllowNonCanonical(bytes.NewReader([]byte{0x92, 0x00})))
+		require.NoError(t, err)
+		assert.Equal(t, varsig.HashSha2_256, h)
+	})
+
+	t.Run("DecodePayloadEncoding", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.DecodePayloadEncoding(bytes.NewReader([]byte{0xdf, 0x80, 0x00}))
+		require.ErrorIs(t, err, varsig.ErrNonCanonicalVarint)
+
+		r := varsig.AllowNonCanonical(varsig.AllowNonCanonical(bytes.NewReader([]byte{0xdf, 0x80, 0x00})))
+		payEnc, err := varsig.DecodePayloadEncoding(r)
+		require.NoError(t, err)
+		assert.Equal(t, varsig.PayloadEncodingVerbatim, payEnc)
+	})
+
+	t.Run("single zero byte is canonical", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := varsig.Decode(mustDecodeHex(t, "340185241200"+"71"))
+		require.NoError(t, err)
+		assert.Equal(t, varsig.RS256(0, varsig.PayloadEncodingDAGCBOR), vs)
+	})
+
+	t.Run("truncated and overflowing varints", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.Decode([]byte{0x34, 0x81})
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		_, err = varsig.Decode(mustDecodeHex(t, "3401852412ffffffffffffffffff7f71"))
+		require.EqualError(t, err, "key-length at offset 5: binary: varint overflows a 64-bit integer")
+	})
+}
@@ -20,7 +20,9 @@ package varsig
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
+	"math/bits"
 )
 
 // Varsig represents types that describe how a signature was generated
@@ -42,10 +44,51 @@ type Varsig interface {
 	Encode() []byte
 }
 
+// Appender is implemented by Varsig types that can encode themselves into
+// a caller-provided buffer without allocating.  All the Varsig types
+// provided by this library implement it.
+type Appender interface {
+	// AppendEncode appends the encoded byte format of the varsig to dst
+	// and returns the extended buffer.
+	AppendEncode(dst []byte) []byte
+
+	// EncodedLen returns the length of the encoded byte format of the
+	// varsig.
+	EncodedLen() int
+}
+
+// AppendEncode appends the encoded byte format of the varsig to dst and
+// returns the extended buffer.  Varsigs that don't implement Appender are
+// encoded with their Encode method and copied.
+func AppendEncode(dst []byte, v Varsig) []byte {
+	if a, ok := v.(Appender); ok {
+		return a.AppendEncode(dst)
+	}
+
+	return append(dst, v.Encode()...)
+}
+
 // Decode converts the provided data into one of the Varsig types
-// provided by the DefaultRegistry.
-func Decode(data []byte) (Varsig, error) {
-	return DefaultRegistry().Decode(data)
+// provided by the DefaultRegistry, with the behavior changed by the
+// provided options (see Registry.Decode.)  Any data following the varsig is
+// ignored; use DecodeStrict to reject it or DecodePrefix to retrieve it.
+func Decode(data []byte, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().Decode(data, opts...)
+}
+
+// DecodeStrict converts the provided data into one of the Varsig types
+// provided by the DefaultRegistry, and returns ErrTrailingBytes if the
+// data continues after the varsig.
+func DecodeStrict(data []byte, opts ...DecodeOption) (Varsig, error) {
+	return DefaultRegistry().DecodeStrict(data, opts...)
+}
+
+// DecodePrefix converts the varsig at the start of the provided data into
+// one of the Varsig types provided by the DefaultRegistry, and returns the
+// data following it (such as the signature when the varsig is
+// concatenated with it.)
+func DecodePrefix(data []byte, opts ...DecodeOption) (Varsig, []byte, error) {
+	return DefaultRegistry().DecodePrefix(data, opts...)
 }
 
 // DecodeStream converts data read from the provided io.Reader into one
@@ -76,20 +119,58 @@ func (v varsig) PayloadEncoding() PayloadEncoding {
 	return v.payEnc
 }
 
-func (v varsig) encode() []byte {
-	// Pre-allocate to the maximum size to avoid re-allocating.
-	// I think the maximum is 10 bytes, but it's all the same for go to allocate 16 (due to the small
-	// size allocation class), so we might as well get some headroom for bigger varints.
-	buf := make([]byte, 0, 16)
+// appendHeader appends the prefix, version and algorithm fields shared by
+// all varsigs to dst.
+func (v varsig) appendHeader(dst []byte) []byte {
+	dst = binary.AppendUvarint(dst, Prefix)
+	dst = binary.AppendUvarint(dst, uint64(Version1))
+	dst = binary.AppendUvarint(dst, uint64(v.algo))
 
-	buf = binary.AppendUvarint(buf, Prefix)
-	buf = binary.AppendUvarint(buf, uint64(Version1))
-	buf = binary.AppendUvarint(buf, uint64(v.algo))
+	return dst
+}
 
-	return buf
+// headerLen returns the encoded length of the fields written by
+// appendHeader.
+func (v varsig) headerLen() int {
+	return uvarintLen(Prefix) + uvarintLen(uint64(Version1)) + uvarintLen(uint64(v.algo))
+}
+
+// uvarintLen returns the number of bytes needed to encode x as an uvarint.
+func uvarintLen(x uint64) int {
+	return (bits.Len64(x|1) + 6) / 7
 }
 
 type BytesReader interface {
 	io.ByteReader
 	io.Reader
 }
+
+// constantName holds the textual representations of an enumerated
+// constant.
+type constantName struct {
+	// text is the human-readable name, which (when one exists) is the
+	// name used by the multicodec table.
+	text string
+
+	// goName is the identifier of the Go constant.
+	goName string
+}
+
+// formatConstant returns the human-readable name of the constant, or its
+// value formatted as a hexadecimal multicodec if it's unknown.
+func formatConstant[T ~uint64](names map[T]constantName, v T) string {
+	if name, ok := names[v]; ok {
+		return name.text
+	}
+
+	return fmt.Sprintf("0x%x", uint64(v))
+}
+
+// goStringConstant returns the Go syntax representation of the constant.
+func goStringConstant[T ~uint64 | ~int](names map[T]constantName, v T, typeName string) string {
+	if name, ok := names[v]; ok {
+		return "varsig." + name.goName
+	}
+
+	return fmt.Sprintf("varsig.%s(0x%x)", typeName, uint64(v))
+}
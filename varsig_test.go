@@ -32,6 +32,21 @@ func ExampleDecode() {
 	// PayloadEncoding: 3
 }
 
+func ExampleDecode_string() {
+	example, err := base64.RawStdEncoding.DecodeString("NAHtAe0BE3E")
+	handleErr(err)
+
+	vs, err := varsig.Decode(example)
+	handleErr(err)
+
+	fmt.Println(vs)
+	fmt.Printf("Hash: %v\n", vs.Hash())
+
+	// Output:
+	// EdDSA/Ed25519/sha2-512/dag-cbor v1
+	// Hash: sha2-512
+}
+
 func ExampleEncode() {
 	edDSAVarsig := varsig.NewEdDSAVarsig(
 		varsig.CurveEd25519,
@@ -49,17 +64,17 @@ func ExampleEncode() {
 func TestDecode(t *testing.T) {
 	t.Parallel()
 
-	t.Run("passes - section 3 example", func(t *testing.T) {
-		t.Skip()
+	t.Run("fails - section 3 example (v0)", func(t *testing.T) {
 		t.Parallel()
+
+		// The example of section 3 of the specification is a v0 header
+		// followed by its signature (see testdata/vectors/header.json.)
 		data, err := hex.DecodeString("34ed01ae3784f03f9ee1163382fa6efa73b0c31ecf58c899c836709303ba4621d1e6df20e09aaa568914290b7ea124f5b38e70b9b69c7de0d216880eac885edd41c302")
 		require.NoError(t, err)
 
-		// TODO
-
 		vs, err := varsig.Decode(data)
-		require.ErrorIs(t, err, varsig.ErrNotYetImplemented)
-		assert.Equal(t, nil, vs)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedVersion)
+		assert.Nil(t, vs)
 	})
 
 	t.Run("fails - no data (empty prefix)", func(t *testing.T) {
@@ -148,6 +163,178 @@ func TestDecode(t *testing.T) {
 	})
 }
 
+func ExampleDecodePrefix() {
+	// A varsig header directly followed by a (truncated) signature.
+	data, err := hex.DecodeString("3401ed01ed011371" + "e5564300c360ac72")
+	handleErr(err)
+
+	vs, sig, err := varsig.DecodePrefix(data)
+	handleErr(err)
+
+	fmt.Println(vs)
+	fmt.Printf("Signature: %x\n", sig)
+
+	// Output:
+	// EdDSA/Ed25519/sha2-512/dag-cbor v1
+	// Signature: e5564300c360ac72
+}
+
+func TestDecodeStrict(t *testing.T) {
+	t.Parallel()
+
+	data := varsig.Ed25519(varsig.PayloadEncodingDAGCBOR).Encode()
+
+	for _, tc := range []struct {
+		name   string
+		decode func([]byte, ...varsig.DecodeOption) (varsig.Varsig, error)
+	}{
+		{name: "DecodeStrict", decode: varsig.DecodeStrict},
+		{name: "Registry.DecodeStrict", decode: varsig.DefaultRegistry().DecodeStrict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vs, err := tc.decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, varsig.Ed25519(varsig.PayloadEncodingDAGCBOR), vs)
+
+			vs, err = tc.decode(append(data, 0x00, 0x01))
+			require.ErrorIs(t, err, varsig.ErrTrailingBytes)
+			require.EqualError(t, err, "trailing bytes after varsig: 2 bytes")
+			assert.Nil(t, vs)
+
+			vs, err = tc.decode(data[:len(data)-1])
+			require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+			assert.Nil(t, vs)
+		})
+	}
+}
+
+func TestDecodePrefix(t *testing.T) {
+	t.Parallel()
+
+	data := varsig.RS256(256, varsig.PayloadEncodingEIP191Raw).Encode()
+
+	for _, tc := range []struct {
+		name   string
+		decode func([]byte, ...varsig.DecodeOption) (varsig.Varsig, []byte, error)
+	}{
+		{name: "DecodePrefix", decode: varsig.DecodePrefix},
+		{name: "Registry.DecodePrefix", decode: varsig.DefaultRegistry().DecodePrefix},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vs, rest, err := tc.decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, varsig.RS256(256, varsig.PayloadEncodingEIP191Raw), vs)
+			assert.Empty(t, rest)
+
+			vs, rest, err = tc.decode(append(data, "signature"...))
+			require.NoError(t, err)
+			assert.Equal(t, varsig.RS256(256, varsig.PayloadEncodingEIP191Raw), vs)
+			assert.Equal(t, []byte("signature"), rest)
+
+			vs, rest, err = tc.decode([]byte{0x34, 0x02})
+			require.ErrorIs(t, err, varsig.ErrUnsupportedVersion)
+			assert.Nil(t, vs)
+			assert.Nil(t, rest)
+		})
+	}
+}
+
+// appendVarsigs are varsigs covering every built-in type, including
+// fields whose uvarints span several bytes.
+var appendVarsigs = []interface {
+	varsig.Varsig
+	varsig.Appender
+	fmt.Stringer
+}{
+	varsig.NewEdDSAVarsig(varsig.CurveEd25519, varsig.HashSha2_512, varsig.PayloadEncodingDAGCBOR),
+	varsig.NewEdDSAVarsig(varsig.CurveEd448, varsig.HashShake_256, varsig.PayloadEncodingWebAuthnCbor),
+	varsig.NewECDSAVarsig(varsig.CurveP256, varsig.HashSha2_256, varsig.PayloadEncodingEIP712),
+	varsig.NewECDSAVarsig(varsig.CurveSecp256k1, varsig.HashKeccak_256, varsig.PayloadEncodingEIP191Raw),
+	varsig.NewRSAVarsig(varsig.HashSha2_256, 256, varsig.PayloadEncodingDAGJSON),
+	varsig.NewRSAVarsig(varsig.HashSha2_224, 1<<40, varsig.PayloadEncodingVerbatim),
+}
+
+func TestAppendEncode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, vs := range appendVarsigs {
+			t.Run(vs.String(), func(t *testing.T) {
+				t.Parallel()
+
+				data := vs.Encode()
+				assert.Len(t, data, vs.EncodedLen())
+				assert.Equal(t, len(data), cap(data))
+				assert.Equal(t, data, vs.AppendEncode(nil))
+				assert.Equal(t, append([]byte("head"), data...), vs.AppendEncode([]byte("head")))
+				assert.Equal(t, data, varsig.AppendEncode(nil, vs))
+
+				decoded, err := varsig.Decode(data, varsig.WithWebAuthn())
+				require.NoError(t, err)
+				assert.Equal(t, vs, decoded)
+			})
+		}
+	})
+
+	t.Run("passes - Varsig without Appender", func(t *testing.T) {
+		t.Parallel()
+
+		vs := varsig.ES256(varsig.PayloadEncodingDAGCBOR)
+		assert.Equal(t, append([]byte{0xff}, vs.Encode()...), varsig.AppendEncode([]byte{0xff}, encodedVarsig{vs}))
+	})
+
+	t.Run("panics - invalid payload encoding", func(t *testing.T) {
+		t.Parallel()
+
+		vs := varsig.NewRSAVarsig(varsig.HashSha2_256, 256, varsig.PayloadEncodingUnspecified)
+		assert.Panics(t, func() { vs.AppendEncode(nil) })
+		assert.Panics(t, func() { vs.Encode() })
+	})
+}
+
+// TestAppendEncodeAllocs can't run in parallel because AllocsPerRun
+// doesn't support it.
+func TestAppendEncodeAllocs(t *testing.T) {
+	for _, vs := range appendVarsigs {
+		buf := make([]byte, 0, 64)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = vs.AppendEncode(buf[:0])
+			_ = vs.EncodedLen()
+		})
+		assert.Zero(t, allocs, vs.String())
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, vs := range appendVarsigs {
+		b.Run(vs.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = vs.Encode()
+			}
+		})
+	}
+}
+
+func BenchmarkAppendEncode(b *testing.B) {
+	for _, vs := range appendVarsigs {
+		b.Run(vs.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			buf := make([]byte, 0, 64)
+			for i := 0; i < b.N; i++ {
+				buf = vs.AppendEncode(buf[:0])
+			}
+		})
+	}
+}
+
 func handleErr(err error) {
 	if err != nil {
 		panic(err)
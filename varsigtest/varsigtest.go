@@ -0,0 +1,245 @@
+// Package varsigtest implements support for testing the decoding
+// functions of third-party signing algorithms, which are added to a
+// varsig.Registry using Register.
+package varsigtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// trailer is appended to the encoded samples to check that the data
+// following a varsig is left alone.
+var trailer = []byte("signature")
+
+// webAuthn is passed to every decoding function, so that the samples may
+// use any payload encoding.
+var webAuthn = varsig.WithWebAuthn()
+
+// errRejected is returned by the policy that rejects every varsig.
+var errRejected = errors.New("varsigtest: rejected")
+
+// TestDecodeFunc tests the decodeFunc parsing the varsigs of a signing
+// algorithm.  It encodes each of the samples (which must be varsigs of
+// the algorithm decoded by decodeFunc, with every field set) and checks
+// that:
+//
+//   - the varsig decodes to a varsig Equal to the sample, which encodes
+//     to the same bytes;
+//   - decoding the varsig truncated at every byte returns an error
+//     wrapping io.EOF or io.ErrUnexpectedEOF;
+//   - data following the varsig isn't consumed;
+//   - decodeFunc is used once it's registered, either in a new Registry
+//     or in the DefaultRegistry;
+//   - the decoded varsig is checked by the policy, and the size limit and
+//     strict decoding are enforced (see varsig.DecodeOption.)
+//
+// The samples are decoded with the varsig.WithWebAuthn option, so they may
+// use any payload encoding.
+//
+// If any of the checks fail, TestDecodeFunc returns an error listing all
+// the problems found.  It's typically called as follows:
+//
+//	if err := varsigtest.TestDecodeFunc(decodeMyAlgorithm, samples...); err != nil {
+//		t.Fatal(err)
+//	}
+func TestDecodeFunc(decodeFunc varsig.DecodeFunc, samples ...varsig.Varsig) error {
+	if len(samples) == 0 {
+		return errors.New("varsigtest: no samples")
+	}
+
+	var errs []error
+
+	for _, sample := range samples {
+		errs = append(errs, testSample(decodeFunc, sample)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// testSample runs every check on a single sample, and returns the
+// problems found.
+func testSample(decodeFunc varsig.DecodeFunc, sample varsig.Varsig) (errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errs = append(errs, fmt.Errorf("varsigtest: %v: panic: %v", sample, r))
+		}
+	}()
+
+	data := sample.Encode()
+	if len(data) == 0 {
+		return []error{fmt.Errorf("varsigtest: %v: the sample can't be encoded", sample)}
+	}
+
+	rs := varsig.NewRegistry()
+	rs.Register(sample.Algorithm(), decodeFunc)
+
+	for _, check := range []struct {
+		name string
+		fn   func(varsig.Registry, varsig.DecodeFunc, varsig.Varsig, []byte) error
+	}{
+		{name: "round-trip", fn: checkRoundTrip},
+		{name: "truncated", fn: checkTruncated},
+		{name: "trailing bytes", fn: checkTrailingBytes},
+		{name: "registry", fn: checkRegistry},
+		{name: "options", fn: checkOptions},
+	} {
+		if err := check.fn(rs, decodeFunc, sample, data); err != nil {
+			errs = append(errs, fmt.Errorf("varsigtest: %v: %s: %w", sample, check.name, err))
+		}
+	}
+
+	return errs
+}
+
+// checkRoundTrip checks that the encoded sample decodes to an equal
+// varsig, which encodes to the same bytes.
+func checkRoundTrip(rs varsig.Registry, _ varsig.DecodeFunc, sample varsig.Varsig, data []byte) error {
+	vs, err := rs.DecodeStrict(data, webAuthn)
+	if err != nil {
+		return fmt.Errorf("decoding %x: %w", data, err)
+	}
+
+	return checkEqual(sample, vs)
+}
+
+// checkTruncated checks that decoding fails with an EOF error when the
+// data ends before the varsig does.
+func checkTruncated(rs varsig.Registry, _ varsig.DecodeFunc, _ varsig.Varsig, data []byte) error {
+	for i := range data {
+		vs, err := rs.Decode(data[:i], webAuthn)
+
+		switch {
+		case err == nil:
+			return fmt.Errorf("decoding %x (truncated at %d bytes) returned %v instead of an error", data[:i], i, vs)
+		case !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF):
+			return fmt.Errorf("decoding %x (truncated at %d bytes) returned %w instead of an EOF error", data[:i], i, err)
+		}
+	}
+
+	return nil
+}
+
+// checkTrailingBytes checks that the data following the varsig isn't
+// consumed, so that it can be read as the signature or the next varsig.
+func checkTrailingBytes(rs varsig.Registry, _ varsig.DecodeFunc, sample varsig.Varsig, data []byte) error {
+	withTrailer := append(bytes.Clone(data), trailer...)
+
+	vs, rest, err := rs.DecodePrefix(withTrailer, webAuthn)
+	if err != nil {
+		return fmt.Errorf("decoding %x: %w", withTrailer, err)
+	}
+
+	if err := checkEqual(sample, vs); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(rest, trailer) {
+		return fmt.Errorf("decoding %x left %x instead of %x", withTrailer, rest, trailer)
+	}
+
+	if _, err := rs.DecodeStrict(withTrailer, webAuthn); !errors.Is(err, varsig.ErrTrailingBytes) {
+		return fmt.Errorf("strictly decoding %x returned %v instead of %v", withTrailer, err, varsig.ErrTrailingBytes)
+	}
+
+	n := 0
+
+	for vs, err := range rs.DecodeSeqBytes(append(bytes.Clone(data), data...), webAuthn) {
+		if err != nil {
+			return fmt.Errorf("decoding the sample twice in a row: %w", err)
+		}
+
+		if err := checkEqual(sample, vs); err != nil {
+			return err
+		}
+
+		n++
+	}
+
+	if n != 2 {
+		return fmt.Errorf("decoding the sample twice in a row returned %d varsigs", n)
+	}
+
+	return nil
+}
+
+// checkRegistry checks that the algorithm is only decoded once it's
+// registered, and that it can be added to the DefaultRegistry.
+func checkRegistry(_ varsig.Registry, decodeFunc varsig.DecodeFunc, sample varsig.Varsig, data []byte) error {
+	if _, err := varsig.NewRegistry().Decode(data, webAuthn); !errors.Is(err, varsig.ErrUnknownAlgorithm) {
+		return fmt.Errorf("decoding without registering the algorithm returned %v instead of %v", err, varsig.ErrUnknownAlgorithm)
+	}
+
+	rs := varsig.DefaultRegistry()
+	rs.Register(sample.Algorithm(), decodeFunc)
+
+	vs, err := rs.Decode(data, webAuthn)
+	if err != nil {
+		return fmt.Errorf("decoding with the DefaultRegistry: %w", err)
+	}
+
+	return checkEqual(sample, vs)
+}
+
+// checkOptions checks that the options of Registry.DecodeWithOptions
+// apply to the decoded varsig.
+func checkOptions(rs varsig.Registry, _ varsig.DecodeFunc, sample varsig.Varsig, data []byte) error {
+	decode := func(data []byte, opts ...varsig.DecodeOption) (varsig.Varsig, error) {
+		return rs.DecodeWithOptions(context.Background(), bytes.NewReader(data), append(opts, webAuthn)...)
+	}
+
+	var checked varsig.Varsig
+
+	vs, err := decode(data, varsig.WithStrict(), varsig.WithMaxSize(len(data)), varsig.WithPolicy(func(vs varsig.Varsig) error {
+		checked = vs
+
+		return nil
+	}))
+	if err != nil {
+		return fmt.Errorf("decoding with options: %w", err)
+	}
+
+	if err := checkEqual(sample, vs); err != nil {
+		return err
+	}
+
+	if err := checkEqual(sample, checked); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+
+	_, err = decode(data, varsig.WithPolicy(func(varsig.Varsig) error { return errRejected }))
+	if !errors.Is(err, varsig.ErrPolicyViolation) || !errors.Is(err, errRejected) {
+		return fmt.Errorf("decoding with a policy rejecting the varsig returned %v instead of %v", err, varsig.ErrPolicyViolation)
+	}
+
+	_, err = decode(data, varsig.WithMaxSize(len(data)-1))
+	if !errors.Is(err, varsig.ErrLimitExceeded) {
+		return fmt.Errorf("decoding with a size limit of %d bytes returned %v instead of %v", len(data)-1, err, varsig.ErrLimitExceeded)
+	}
+
+	_, err = decode(append(bytes.Clone(data), trailer...), varsig.WithStrict())
+	if !errors.Is(err, varsig.ErrTrailingBytes) {
+		return fmt.Errorf("strictly decoding with options returned %v instead of %v", err, varsig.ErrTrailingBytes)
+	}
+
+	return nil
+}
+
+// checkEqual checks that the decoded varsig is equal to the sample and
+// encodes to the same bytes.
+func checkEqual(sample, vs varsig.Varsig) error {
+	if !varsig.Equal(sample, vs) {
+		return fmt.Errorf("decoded %v instead of %v", vs, sample)
+	}
+
+	if got, want := vs.Encode(), sample.Encode(); !bytes.Equal(got, want) {
+		return fmt.Errorf("decoded varsig encodes to %x instead of %x", got, want)
+	}
+
+	return nil
+}
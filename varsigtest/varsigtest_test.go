@@ -0,0 +1,200 @@
+package varsigtest_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+	"github.com/ucan-wg/go-varsig/varsigtest"
+)
+
+// AlgorithmExample is a third-party signing algorithm whose varsigs hold
+// a hash algorithm and a payload encoding.
+const AlgorithmExample = varsig.Algorithm(0x1000)
+
+type ExampleVarsig struct {
+	hashAlg varsig.Hash
+	payEnc  varsig.PayloadEncoding
+}
+
+func (v ExampleVarsig) Version() varsig.Version                 { return varsig.Version1 }
+func (v ExampleVarsig) Algorithm() varsig.Algorithm             { return AlgorithmExample }
+func (v ExampleVarsig) Hash() varsig.Hash                       { return v.hashAlg }
+func (v ExampleVarsig) PayloadEncoding() varsig.PayloadEncoding { return v.payEnc }
+
+func (v ExampleVarsig) String() string {
+	return fmt.Sprintf("example/%v/%v", v.hashAlg, v.payEnc)
+}
+
+func (v ExampleVarsig) Encode() []byte {
+	buf := binary.AppendUvarint(nil, varsig.Prefix)
+	buf = binary.AppendUvarint(buf, uint64(varsig.Version1))
+	buf = binary.AppendUvarint(buf, uint64(AlgorithmExample))
+	buf = binary.AppendUvarint(buf, uint64(v.hashAlg))
+
+	return append(buf, varsig.EncodePayloadEncoding(v.payEnc)...)
+}
+
+func DecodeExample(r varsig.BytesReader) (varsig.Varsig, error) {
+	hashAlg, err := varsig.DecodeHashAlgorithm(r)
+	if err != nil {
+		return nil, err
+	}
+
+	payEnc, err := varsig.DecodePayloadEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExampleVarsig{hashAlg: hashAlg, payEnc: payEnc}, nil
+}
+
+func ExampleTestDecodeFunc() {
+	err := varsigtest.TestDecodeFunc(DecodeExample,
+		ExampleVarsig{hashAlg: varsig.HashSha2_256, payEnc: varsig.PayloadEncodingDAGCBOR},
+		ExampleVarsig{hashAlg: varsig.HashBlake2b_256, payEnc: varsig.PayloadEncodingEIP191Raw},
+	)
+	fmt.Println(err)
+	// Output:
+	// <nil>
+}
+
+func TestTestDecodeFunc(t *testing.T) {
+	t.Parallel()
+
+	samples := []varsig.Varsig{
+		ExampleVarsig{hashAlg: varsig.HashSha2_256, payEnc: varsig.PayloadEncodingVerbatim},
+		ExampleVarsig{hashAlg: varsig.HashBlake2b_512, payEnc: varsig.PayloadEncodingWebAuthnCbor},
+	}
+
+	t.Run("passes", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, varsigtest.TestDecodeFunc(DecodeExample, samples...))
+	})
+
+	t.Run("passes - built-in algorithms", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			algo    varsig.Algorithm
+			samples []varsig.Varsig
+		}{
+			{
+				algo: varsig.AlgorithmEdDSA,
+				samples: []varsig.Varsig{
+					varsig.Ed25519(varsig.PayloadEncodingDAGCBOR),
+					varsig.Ed448(varsig.PayloadEncodingEIP191Cbor),
+				},
+			},
+			{
+				algo: varsig.AlgorithmECDSA,
+				samples: []varsig.Varsig{
+					varsig.ES256(varsig.PayloadEncodingDAGJSON),
+					varsig.ES256K(varsig.PayloadEncodingEIP712),
+				},
+			},
+			{
+				algo: varsig.AlgorithmRSA,
+				samples: []varsig.Varsig{
+					varsig.RS256(256, varsig.PayloadEncodingVerbatim),
+					varsig.NewRSAVarsig(varsig.HashSha2_512, 1<<40, varsig.PayloadEncodingWebAuthnRaw),
+				},
+			},
+		} {
+			require.NoError(t, varsigtest.TestDecodeFunc(varsig.DefaultRegistry()[tc.algo], tc.samples...))
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name       string
+			decodeFunc varsig.DecodeFunc
+			samples    []varsig.Varsig
+			errMsg     string
+		}{
+			{
+				name:       "no samples",
+				decodeFunc: DecodeExample,
+				errMsg:     "varsigtest: no samples",
+			},
+			{
+				name: "field ignored",
+				decodeFunc: func(r varsig.BytesReader) (varsig.Varsig, error) {
+					vs, err := DecodeExample(r)
+					if err != nil {
+						return nil, err
+					}
+
+					return ExampleVarsig{hashAlg: vs.Hash(), payEnc: varsig.PayloadEncodingDAGCBOR}, nil
+				},
+				samples: samples[:1],
+				errMsg:  "varsigtest: example/sha2-256/verbatim: round-trip: decoded example/sha2-256/dag-cbor instead of example/sha2-256/verbatim",
+			},
+			{
+				name: "EOF not reported",
+				decodeFunc: func(r varsig.BytesReader) (varsig.Varsig, error) {
+					vs, err := DecodeExample(r)
+					if err != nil {
+						return samples[0], nil
+					}
+
+					return vs, nil
+				},
+				samples: samples[:1],
+				errMsg:  "varsigtest: example/sha2-256/verbatim: truncated: decoding 34018020 (truncated at 4 bytes) returned example/sha2-256/verbatim instead of an error",
+			},
+			{
+				name: "trailing bytes consumed",
+				decodeFunc: func(r varsig.BytesReader) (varsig.Varsig, error) {
+					vs, err := DecodeExample(r)
+					if err != nil {
+						return nil, err
+					}
+
+					_, err = io.Copy(io.Discard, r)
+
+					return vs, err
+				},
+				samples: samples[:1],
+				errMsg:  "varsigtest: example/sha2-256/verbatim: trailing bytes: decoding 3401802012",
+			},
+			{
+				name: "wrong algorithm",
+				decodeFunc: func(r varsig.BytesReader) (varsig.Varsig, error) {
+					_, err := DecodeExample(r)
+					if err != nil {
+						return nil, err
+					}
+
+					return varsig.Ed25519(varsig.PayloadEncodingVerbatim), nil
+				},
+				samples: samples[:1],
+				errMsg:  "varsigtest: example/sha2-256/verbatim: round-trip: decoded EdDSA/Ed25519/sha2-512/verbatim v1 instead of example/sha2-256/verbatim",
+			},
+			{
+				name: "panic",
+				decodeFunc: func(r varsig.BytesReader) (varsig.Varsig, error) {
+					panic("not implemented")
+				},
+				samples: samples[:1],
+				errMsg:  "varsigtest: example/sha2-256/verbatim: panic: not implemented",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				err := varsigtest.TestDecodeFunc(tc.decodeFunc, tc.samples...)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+}
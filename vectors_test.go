@@ -0,0 +1,157 @@
+package varsig_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+// vectorFile is a file of the test-vectors in testdata/vectors (see the
+// README.md file in that directory.)  Source is set when the vectors come
+// from another implementation.
+type vectorFile struct {
+	Description string   `json:"description"`
+	Source      string   `json:"source,omitempty"`
+	Vectors     []vector `json:"vectors"`
+}
+
+// vector is a single test-vector.  Exactly one of Varsig and Error is
+// set.  GoSpecific explains why the expected result is specific to this
+// library, and Options lists the decoding options it requires.
+type vector struct {
+	Description string            `json:"description"`
+	GoSpecific  string            `json:"go-specific,omitempty"`
+	Options     []string          `json:"options,omitempty"`
+	Hex         string            `json:"hex"`
+	Varsig      map[string]string `json:"varsig,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// vectorErrors maps the error classes of the test-vectors to the errors
+// they match.
+var vectorErrors = map[string][]error{
+	"bad-prefix":                   {varsig.ErrBadPrefix},
+	"unsupported-version":          {varsig.ErrUnsupportedVersion},
+	"unknown-algorithm":            {varsig.ErrUnknownAlgorithm},
+	"unknown-curve":                {varsig.ErrUnknownEdDSACurve, varsig.ErrUnknownECDSACurve},
+	"unknown-hash":                 {varsig.ErrUnknownHash},
+	"unsupported-payload-encoding": {varsig.ErrUnsupportedPayloadEncoding},
+	"non-canonical-varint":         {varsig.ErrNonCanonicalVarint},
+	"truncated":                    {io.EOF, io.ErrUnexpectedEOF},
+}
+
+// vectorOptions maps the options of the test-vectors to DecodeOptions.
+var vectorOptions = map[string]varsig.DecodeOption{
+	"webauthn": varsig.WithWebAuthn(),
+}
+
+func TestVectors(t *testing.T) {
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "vectors", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var file vectorFile
+		require.NoError(t, json.Unmarshal(data, &file))
+		require.NotEmpty(t, file.Vectors, path)
+
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			t.Parallel()
+
+			for _, v := range file.Vectors {
+				t.Run(v.Description, func(t *testing.T) {
+					t.Parallel()
+
+					data, err := hex.DecodeString(v.Hex)
+					require.NoError(t, err)
+
+					var opts []varsig.DecodeOption
+
+					for _, name := range v.Options {
+						opt, ok := vectorOptions[name]
+						require.True(t, ok, "unknown option %q", name)
+
+						opts = append(opts, opt)
+					}
+
+					if v.Error != "" {
+						require.Nil(t, v.Varsig, "vector has both a varsig and an error")
+						checkVectorError(t, data, v.Error, opts...)
+
+						return
+					}
+
+					// The options are required to decode the vector.
+					if len(opts) > 0 {
+						checkVectorError(t, data, "unsupported-payload-encoding")
+					}
+
+					vs, err := varsig.DecodeStrict(data, opts...)
+					require.NoError(t, err)
+					assert.Equal(t, v.Varsig, vectorFields(vs))
+					assert.Equal(t, data, vs.Encode())
+
+					fast, n, err := varsig.DecodeBytes(data, opts...)
+					require.NoError(t, err)
+					assert.Equal(t, vs, fast)
+					assert.Equal(t, len(data), n)
+				})
+			}
+		})
+	}
+}
+
+// checkVectorError checks that both Decode and DecodeBytes fail to decode
+// the data with an error of the provided class.
+func checkVectorError(t *testing.T, data []byte, class string, opts ...varsig.DecodeOption) {
+	t.Helper()
+
+	targets, ok := vectorErrors[class]
+	require.True(t, ok, "unknown error class %q", class)
+
+	matches := func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	vs, err := varsig.Decode(data, opts...)
+	require.Error(t, err)
+	assert.True(t, matches(err), "%v isn't a %s error", err, class)
+	assert.Nil(t, vs)
+
+	vs, _, err = varsig.DecodeBytes(data, opts...)
+	require.Error(t, err)
+	assert.True(t, matches(err), "%v isn't a %s error", err, class)
+	assert.Nil(t, vs)
+}
+
+// vectorFields returns the text of each of the varsig's parameters, keyed
+// by the parameter's name.
+func vectorFields(vs varsig.Varsig) map[string]string {
+	fields := make(map[string]string)
+
+	for _, p := range varsig.Describe(vs) {
+		fields[p.Name] = p.Text
+	}
+
+	return fields
+}
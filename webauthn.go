@@ -0,0 +1,101 @@
+package varsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// WebAuthn authenticators don't sign the payload directly.  Instead, the
+// relying party provides a challenge which the client embeds in a JSON
+// document (the client data) and the authenticator signs:
+//
+//	authenticatorData || SHA-256(clientDataJSON)
+//
+// Varsigs using PayloadEncodingWebAuthnRaw or PayloadEncodingWebAuthnCbor
+// describe signatures where the challenge is the SHA-256 hash of the
+// (verbatim or DAG-CBOR encoded) payload.  The WebAuthnAssertion type
+// can then reconstruct the bytes that were actually signed.
+//
+// No multicodec is registered for WebAuthn yet, so these payload
+// encodings use a private-use value and are only decoded with the
+// WithWebAuthn option, or when unmarshaling what this library marshaled.
+//
+// See https://www.w3.org/TR/webauthn-3/#sctn-op-get-assertion
+
+// webAuthnMinAuthenticatorDataLen is the length of the rpIdHash, flags
+// and signCount fields which are always present in authenticator data.
+const webAuthnMinAuthenticatorDataLen = 37
+
+// webAuthnTypeGet is the value of the client data "type" field when
+// a credential is asserted.
+const webAuthnTypeGet = "webauthn.get"
+
+// IsWebAuthn returns true if the varsig describes a signature produced
+// by a WebAuthn authenticator.
+func IsWebAuthn(v Varsig) bool {
+	return isWebAuthnPayloadEncoding(v.PayloadEncoding())
+}
+
+func isWebAuthnPayloadEncoding(enc PayloadEncoding) bool {
+	return enc == PayloadEncodingWebAuthnRaw || enc == PayloadEncodingWebAuthnCbor
+}
+
+// WebAuthnChallenge returns the challenge that must be provided to the
+// WebAuthn authenticator in order to sign the payload.
+func WebAuthnChallenge(payload []byte) []byte {
+	sum := sha256.Sum256(payload)
+	return sum[:]
+}
+
+// WebAuthnAssertion contains the data returned by a WebAuthn
+// authenticator alongside the signature when asserting a credential.
+type WebAuthnAssertion struct {
+	AuthenticatorData []byte
+	ClientDataJSON    []byte
+}
+
+// webAuthnClientData contains the client data fields that are checked
+// to bind an assertion to a payload.
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// SignedData verifies that the assertion's client data is bound to the
+// provided payload and returns the bytes that were signed by the
+// authenticator.  The returned value should be verified against the
+// signature using the algorithm, curve and hash of the varsig.
+func (a WebAuthnAssertion) SignedData(payload []byte) ([]byte, error) {
+	if len(a.AuthenticatorData) < webAuthnMinAuthenticatorDataLen {
+		return nil, fmt.Errorf("%w: authenticator data too short: %d bytes", ErrInvalidWebAuthnAssertion, len(a.AuthenticatorData))
+	}
+
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(a.ClientDataJSON, &clientData); err != nil {
+		return nil, fmt.Errorf("%w: malformed client data: %w", ErrInvalidWebAuthnAssertion, err)
+	}
+
+	if clientData.Type != webAuthnTypeGet {
+		return nil, fmt.Errorf("%w: unexpected client data type: %q", ErrInvalidWebAuthnAssertion, clientData.Type)
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(clientData.Challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed challenge: %w", ErrInvalidWebAuthnAssertion, err)
+	}
+
+	if !bytes.Equal(challenge, WebAuthnChallenge(payload)) {
+		return nil, fmt.Errorf("%w: challenge doesn't match payload", ErrInvalidWebAuthnAssertion)
+	}
+
+	clientDataHash := sha256.Sum256(a.ClientDataJSON)
+
+	signed := make([]byte, 0, len(a.AuthenticatorData)+len(clientDataHash))
+	signed = append(signed, a.AuthenticatorData...)
+	signed = append(signed, clientDataHash[:]...)
+
+	return signed, nil
+}
@@ -0,0 +1,172 @@
+package varsig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ucan-wg/go-varsig"
+)
+
+func TestWebAuthnAssertion(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("example UCAN payload")
+	authData := make([]byte, 37)
+	authData[32] = 0x05 // UP and UV flags
+
+	clientData := func(typ string, challenge []byte) []byte {
+		return []byte(fmt.Sprintf(
+			`{"type":%q,"challenge":%q,"origin":"https://example.com","crossOrigin":false}`,
+			typ, base64.RawURLEncoding.EncodeToString(challenge),
+		))
+	}
+
+	t.Run("passes - ES256 signature verifies", func(t *testing.T) {
+		t.Parallel()
+
+		vs, err := varsig.WebAuthnES256(varsig.PayloadEncodingWebAuthnCbor)
+		require.NoError(t, err)
+		require.True(t, varsig.IsWebAuthn(vs))
+
+		_, err = varsig.Decode(vs.Encode())
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+
+		rt, err := varsig.Decode(vs.Encode(), varsig.WithWebAuthn())
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		assertion := varsig.WebAuthnAssertion{
+			AuthenticatorData: authData,
+			ClientDataJSON:    clientData("webauthn.get", varsig.WebAuthnChallenge(payload)),
+		}
+
+		// What the authenticator does
+		signed, err := assertion.SignedData(payload)
+		require.NoError(t, err)
+		digest := sha256.Sum256(signed)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+		require.NoError(t, err)
+
+		// What the verifier does
+		signed, err = assertion.SignedData(payload)
+		require.NoError(t, err)
+		require.Len(t, signed, len(authData)+sha256.Size)
+		digest = sha256.Sum256(signed)
+		require.True(t, ecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig))
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name      string
+			assertion varsig.WebAuthnAssertion
+		}{
+			{
+				name: "authenticator data too short",
+				assertion: varsig.WebAuthnAssertion{
+					AuthenticatorData: authData[:36],
+					ClientDataJSON:    clientData("webauthn.get", varsig.WebAuthnChallenge(payload)),
+				},
+			},
+			{
+				name: "malformed client data",
+				assertion: varsig.WebAuthnAssertion{
+					AuthenticatorData: authData,
+					ClientDataJSON:    []byte("{"),
+				},
+			},
+			{
+				name: "wrong client data type",
+				assertion: varsig.WebAuthnAssertion{
+					AuthenticatorData: authData,
+					ClientDataJSON:    clientData("webauthn.create", varsig.WebAuthnChallenge(payload)),
+				},
+			},
+			{
+				name: "challenge mismatch",
+				assertion: varsig.WebAuthnAssertion{
+					AuthenticatorData: authData,
+					ClientDataJSON:    clientData("webauthn.get", varsig.WebAuthnChallenge([]byte("other payload"))),
+				},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				signed, err := tc.assertion.SignedData(payload)
+				require.ErrorIs(t, err, varsig.ErrInvalidWebAuthnAssertion)
+				require.Nil(t, signed)
+			})
+		}
+	})
+
+	t.Run("fails - unsupported payload encoding", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.WebAuthnES256(varsig.PayloadEncodingDAGCBOR)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+
+		_, err = varsig.WebAuthnEd25519(varsig.PayloadEncodingEIP191Raw)
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+	})
+}
+
+func TestWebAuthnMarshal(t *testing.T) {
+	t.Parallel()
+
+	vs, err := varsig.WebAuthnES256(varsig.PayloadEncodingWebAuthnCbor)
+	require.NoError(t, err)
+
+	t.Run("passes - JSON", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(vs)
+		require.NoError(t, err)
+		require.Equal(t, `"uNAHsAYAkEoCAwAFx"`, string(data))
+
+		var rt varsig.ECDSAVarsig
+		require.NoError(t, json.Unmarshal(data, &rt))
+		require.Equal(t, vs, rt)
+
+		var a varsig.Any
+		require.NoError(t, json.Unmarshal(data, &a))
+		require.Equal(t, vs, a.Varsig)
+	})
+
+	t.Run("passes - IPLD", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := varsig.MarshalDAGCBOR(vs)
+		require.NoError(t, err)
+
+		rt, err := varsig.UnmarshalDAGCBOR(data)
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+
+		data, err = varsig.MarshalDAGJSON(vs)
+		require.NoError(t, err)
+
+		rt, err = varsig.UnmarshalDAGJSON(data)
+		require.NoError(t, err)
+		require.Equal(t, vs, rt)
+	})
+
+	t.Run("fails - decoding without WithWebAuthn", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := varsig.Decode(vs.Encode())
+		require.ErrorIs(t, err, varsig.ErrUnsupportedPayloadEncoding)
+	})
+}